@@ -0,0 +1,5 @@
+// Package newsv1 содержит gRPC API шлюза, сгенерированный из news.proto,
+// и protobuf-ответы REST API из payloads.proto.
+package newsv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative news/v1/news.proto news/v1/payloads.proto
@@ -0,0 +1,906 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: news/v1/payloads.proto
+
+package newsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PageInfo - пагинация списочного ответа.
+type PageInfo struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// page не заполняется при курсорной пагинации.
+	Page       int32 `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`
+	Limit      int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Total      int32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	TotalPages int32 `protobuf:"varint,4,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	HasNext    bool  `protobuf:"varint,5,opt,name=has_next,json=hasNext,proto3" json:"has_next,omitempty"`
+	// next и prev - ссылки на соседние страницы.
+	Next          string `protobuf:"bytes,6,opt,name=next,proto3" json:"next,omitempty"`
+	Prev          string `protobuf:"bytes,7,opt,name=prev,proto3" json:"prev,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PageInfo) Reset() {
+	*x = PageInfo{}
+	mi := &file_news_v1_payloads_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PageInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PageInfo) ProtoMessage() {}
+
+func (x *PageInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PageInfo.ProtoReflect.Descriptor instead.
+func (*PageInfo) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PageInfo) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *PageInfo) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *PageInfo) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *PageInfo) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+func (x *PageInfo) GetHasNext() bool {
+	if x != nil {
+		return x.HasNext
+	}
+	return false
+}
+
+func (x *PageInfo) GetNext() string {
+	if x != nil {
+		return x.Next
+	}
+	return ""
+}
+
+func (x *PageInfo) GetPrev() string {
+	if x != nil {
+		return x.Prev
+	}
+	return ""
+}
+
+// NewsPage - страница ленты новостей.
+type NewsPage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*News                `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Page          *PageInfo              `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewsPage) Reset() {
+	*x = NewsPage{}
+	mi := &file_news_v1_payloads_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewsPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewsPage) ProtoMessage() {}
+
+func (x *NewsPage) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewsPage.ProtoReflect.Descriptor instead.
+func (*NewsPage) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NewsPage) GetItems() []*News {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *NewsPage) GetPage() *PageInfo {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+// SearchHit - найденная новость с оценкой и подсветкой совпадений.
+type SearchHit struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	News             *News                  `protobuf:"bytes,1,opt,name=news,proto3" json:"news,omitempty"`
+	Score            float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	HighlightTitle   string                 `protobuf:"bytes,3,opt,name=highlight_title,json=highlightTitle,proto3" json:"highlight_title,omitempty"`
+	HighlightSnippet string                 `protobuf:"bytes,4,opt,name=highlight_snippet,json=highlightSnippet,proto3" json:"highlight_snippet,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_news_v1_payloads_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchHit) GetNews() *News {
+	if x != nil {
+		return x.News
+	}
+	return nil
+}
+
+func (x *SearchHit) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *SearchHit) GetHighlightTitle() string {
+	if x != nil {
+		return x.HighlightTitle
+	}
+	return ""
+}
+
+func (x *SearchHit) GetHighlightSnippet() string {
+	if x != nil {
+		return x.HighlightSnippet
+	}
+	return ""
+}
+
+// SearchPage - страница результатов поиска.
+type SearchPage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*SearchHit           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Page          *PageInfo              `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPage) Reset() {
+	*x = SearchPage{}
+	mi := &file_news_v1_payloads_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPage) ProtoMessage() {}
+
+func (x *SearchPage) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPage.ProtoReflect.Descriptor instead.
+func (*SearchPage) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SearchPage) GetItems() []*SearchHit {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *SearchPage) GetPage() *PageInfo {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+// CommentNode - комментарий с ответами.
+type CommentNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Comment       *Comment               `protobuf:"bytes,1,opt,name=comment,proto3" json:"comment,omitempty"`
+	Depth         int32                  `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"`
+	Replies       []*CommentNode         `protobuf:"bytes,3,rep,name=replies,proto3" json:"replies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommentNode) Reset() {
+	*x = CommentNode{}
+	mi := &file_news_v1_payloads_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentNode) ProtoMessage() {}
+
+func (x *CommentNode) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentNode.ProtoReflect.Descriptor instead.
+func (*CommentNode) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CommentNode) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+func (x *CommentNode) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *CommentNode) GetReplies() []*CommentNode {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+// CommentThread - страница ветки комментариев новости.
+type CommentThread struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Items    []*CommentNode         `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	Page     *PageInfo              `protobuf:"bytes,2,opt,name=page,proto3" json:"page,omitempty"`
+	NewsId   int64                  `protobuf:"varint,3,opt,name=news_id,json=newsId,proto3" json:"news_id,omitempty"`
+	ParentId *int64                 `protobuf:"varint,4,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	// view - tree или flat.
+	View          string `protobuf:"bytes,5,opt,name=view,proto3" json:"view,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommentThread) Reset() {
+	*x = CommentThread{}
+	mi := &file_news_v1_payloads_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentThread) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentThread) ProtoMessage() {}
+
+func (x *CommentThread) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentThread.ProtoReflect.Descriptor instead.
+func (*CommentThread) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CommentThread) GetItems() []*CommentNode {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CommentThread) GetPage() *PageInfo {
+	if x != nil {
+		return x.Page
+	}
+	return nil
+}
+
+func (x *CommentThread) GetNewsId() int64 {
+	if x != nil {
+		return x.NewsId
+	}
+	return 0
+}
+
+func (x *CommentThread) GetParentId() int64 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
+func (x *CommentThread) GetView() string {
+	if x != nil {
+		return x.View
+	}
+	return ""
+}
+
+// NewsDetail - новость с деревом комментариев.
+type NewsDetail struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	News          *News                  `protobuf:"bytes,1,opt,name=news,proto3" json:"news,omitempty"`
+	Comments      []*CommentNode         `protobuf:"bytes,2,rep,name=comments,proto3" json:"comments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewsDetail) Reset() {
+	*x = NewsDetail{}
+	mi := &file_news_v1_payloads_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewsDetail) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewsDetail) ProtoMessage() {}
+
+func (x *NewsDetail) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewsDetail.ProtoReflect.Descriptor instead.
+func (*NewsDetail) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *NewsDetail) GetNews() *News {
+	if x != nil {
+		return x.News
+	}
+	return nil
+}
+
+func (x *NewsDetail) GetComments() []*CommentNode {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+// Source - источник новостей.
+type Source struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	LastFetch     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_fetch,json=lastFetch,proto3" json:"last_fetch,omitempty"`
+	ArticleCount  int32                  `protobuf:"varint,4,opt,name=article_count,json=articleCount,proto3" json:"article_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Source) Reset() {
+	*x = Source{}
+	mi := &file_news_v1_payloads_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Source) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Source) ProtoMessage() {}
+
+func (x *Source) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Source.ProtoReflect.Descriptor instead.
+func (*Source) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Source) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Source) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Source) GetLastFetch() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastFetch
+	}
+	return nil
+}
+
+func (x *Source) GetArticleCount() int32 {
+	if x != nil {
+		return x.ArticleCount
+	}
+	return 0
+}
+
+// SourceList - каталог источников.
+type SourceList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Source              `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SourceList) Reset() {
+	*x = SourceList{}
+	mi := &file_news_v1_payloads_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SourceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SourceList) ProtoMessage() {}
+
+func (x *SourceList) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SourceList.ProtoReflect.Descriptor instead.
+func (*SourceList) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SourceList) GetItems() []*Source {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// NewsBatch - ответ пакетного запроса новостей по id.
+type NewsBatch struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Items         map[int64]*NewsBatchItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewsBatch) Reset() {
+	*x = NewsBatch{}
+	mi := &file_news_v1_payloads_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewsBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewsBatch) ProtoMessage() {}
+
+func (x *NewsBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewsBatch.ProtoReflect.Descriptor instead.
+func (*NewsBatch) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *NewsBatch) GetItems() map[int64]*NewsBatchItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// NewsBatchItem - новость или ошибка по одному id.
+type NewsBatchItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	News          *News                  `protobuf:"bytes,1,opt,name=news,proto3" json:"news,omitempty"`
+	Error         *BatchError            `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewsBatchItem) Reset() {
+	*x = NewsBatchItem{}
+	mi := &file_news_v1_payloads_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewsBatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewsBatchItem) ProtoMessage() {}
+
+func (x *NewsBatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewsBatchItem.ProtoReflect.Descriptor instead.
+func (*NewsBatchItem) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *NewsBatchItem) GetNews() *News {
+	if x != nil {
+		return x.News
+	}
+	return nil
+}
+
+func (x *NewsBatchItem) GetError() *BatchError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+// BatchError - ошибка по элементу пакетного запроса.
+type BatchError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchError) Reset() {
+	*x = BatchError{}
+	mi := &file_news_v1_payloads_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchError) ProtoMessage() {}
+
+func (x *BatchError) ProtoReflect() protoreflect.Message {
+	mi := &file_news_v1_payloads_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchError.ProtoReflect.Descriptor instead.
+func (*BatchError) Descriptor() ([]byte, []int) {
+	return file_news_v1_payloads_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchError) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *BatchError) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *BatchError) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+var File_news_v1_payloads_proto protoreflect.FileDescriptor
+
+const file_news_v1_payloads_proto_rawDesc = "" +
+	"\n" +
+	"\x16news/v1/payloads.proto\x12\anews.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x12news/v1/news.proto\"\xae\x01\n" +
+	"\bPageInfo\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\x12\x1f\n" +
+	"\vtotal_pages\x18\x04 \x01(\x05R\n" +
+	"totalPages\x12\x19\n" +
+	"\bhas_next\x18\x05 \x01(\bR\ahasNext\x12\x12\n" +
+	"\x04next\x18\x06 \x01(\tR\x04next\x12\x12\n" +
+	"\x04prev\x18\a \x01(\tR\x04prev\"V\n" +
+	"\bNewsPage\x12#\n" +
+	"\x05items\x18\x01 \x03(\v2\r.news.v1.NewsR\x05items\x12%\n" +
+	"\x04page\x18\x02 \x01(\v2\x11.news.v1.PageInfoR\x04page\"\x9a\x01\n" +
+	"\tSearchHit\x12!\n" +
+	"\x04news\x18\x01 \x01(\v2\r.news.v1.NewsR\x04news\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12'\n" +
+	"\x0fhighlight_title\x18\x03 \x01(\tR\x0ehighlightTitle\x12+\n" +
+	"\x11highlight_snippet\x18\x04 \x01(\tR\x10highlightSnippet\"]\n" +
+	"\n" +
+	"SearchPage\x12(\n" +
+	"\x05items\x18\x01 \x03(\v2\x12.news.v1.SearchHitR\x05items\x12%\n" +
+	"\x04page\x18\x02 \x01(\v2\x11.news.v1.PageInfoR\x04page\"\x7f\n" +
+	"\vCommentNode\x12*\n" +
+	"\acomment\x18\x01 \x01(\v2\x10.news.v1.CommentR\acomment\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\x05R\x05depth\x12.\n" +
+	"\areplies\x18\x03 \x03(\v2\x14.news.v1.CommentNodeR\areplies\"\xbf\x01\n" +
+	"\rCommentThread\x12*\n" +
+	"\x05items\x18\x01 \x03(\v2\x14.news.v1.CommentNodeR\x05items\x12%\n" +
+	"\x04page\x18\x02 \x01(\v2\x11.news.v1.PageInfoR\x04page\x12\x17\n" +
+	"\anews_id\x18\x03 \x01(\x03R\x06newsId\x12 \n" +
+	"\tparent_id\x18\x04 \x01(\x03H\x00R\bparentId\x88\x01\x01\x12\x12\n" +
+	"\x04view\x18\x05 \x01(\tR\x04viewB\f\n" +
+	"\n" +
+	"_parent_id\"a\n" +
+	"\n" +
+	"NewsDetail\x12!\n" +
+	"\x04news\x18\x01 \x01(\v2\r.news.v1.NewsR\x04news\x120\n" +
+	"\bcomments\x18\x02 \x03(\v2\x14.news.v1.CommentNodeR\bcomments\"\x8e\x01\n" +
+	"\x06Source\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x129\n" +
+	"\n" +
+	"last_fetch\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tlastFetch\x12#\n" +
+	"\rarticle_count\x18\x04 \x01(\x05R\farticleCount\"3\n" +
+	"\n" +
+	"SourceList\x12%\n" +
+	"\x05items\x18\x01 \x03(\v2\x0f.news.v1.SourceR\x05items\"\x92\x01\n" +
+	"\tNewsBatch\x123\n" +
+	"\x05items\x18\x01 \x03(\v2\x1d.news.v1.NewsBatch.ItemsEntryR\x05items\x1aP\n" +
+	"\n" +
+	"ItemsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12,\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.news.v1.NewsBatchItemR\x05value:\x028\x01\"]\n" +
+	"\rNewsBatchItem\x12!\n" +
+	"\x04news\x18\x01 \x01(\v2\r.news.v1.NewsR\x04news\x12)\n" +
+	"\x05error\x18\x02 \x01(\v2\x13.news.v1.BatchErrorR\x05error\"R\n" +
+	"\n" +
+	"BatchError\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06detail\x18\x03 \x01(\tR\x06detailB\x1fZ\x1dapigateway/api/news/v1;newsv1b\x06proto3"
+
+var (
+	file_news_v1_payloads_proto_rawDescOnce sync.Once
+	file_news_v1_payloads_proto_rawDescData []byte
+)
+
+func file_news_v1_payloads_proto_rawDescGZIP() []byte {
+	file_news_v1_payloads_proto_rawDescOnce.Do(func() {
+		file_news_v1_payloads_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_news_v1_payloads_proto_rawDesc), len(file_news_v1_payloads_proto_rawDesc)))
+	})
+	return file_news_v1_payloads_proto_rawDescData
+}
+
+var file_news_v1_payloads_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_news_v1_payloads_proto_goTypes = []any{
+	(*PageInfo)(nil),              // 0: news.v1.PageInfo
+	(*NewsPage)(nil),              // 1: news.v1.NewsPage
+	(*SearchHit)(nil),             // 2: news.v1.SearchHit
+	(*SearchPage)(nil),            // 3: news.v1.SearchPage
+	(*CommentNode)(nil),           // 4: news.v1.CommentNode
+	(*CommentThread)(nil),         // 5: news.v1.CommentThread
+	(*NewsDetail)(nil),            // 6: news.v1.NewsDetail
+	(*Source)(nil),                // 7: news.v1.Source
+	(*SourceList)(nil),            // 8: news.v1.SourceList
+	(*NewsBatch)(nil),             // 9: news.v1.NewsBatch
+	(*NewsBatchItem)(nil),         // 10: news.v1.NewsBatchItem
+	(*BatchError)(nil),            // 11: news.v1.BatchError
+	nil,                           // 12: news.v1.NewsBatch.ItemsEntry
+	(*News)(nil),                  // 13: news.v1.News
+	(*Comment)(nil),               // 14: news.v1.Comment
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_news_v1_payloads_proto_depIdxs = []int32{
+	13, // 0: news.v1.NewsPage.items:type_name -> news.v1.News
+	0,  // 1: news.v1.NewsPage.page:type_name -> news.v1.PageInfo
+	13, // 2: news.v1.SearchHit.news:type_name -> news.v1.News
+	2,  // 3: news.v1.SearchPage.items:type_name -> news.v1.SearchHit
+	0,  // 4: news.v1.SearchPage.page:type_name -> news.v1.PageInfo
+	14, // 5: news.v1.CommentNode.comment:type_name -> news.v1.Comment
+	4,  // 6: news.v1.CommentNode.replies:type_name -> news.v1.CommentNode
+	4,  // 7: news.v1.CommentThread.items:type_name -> news.v1.CommentNode
+	0,  // 8: news.v1.CommentThread.page:type_name -> news.v1.PageInfo
+	13, // 9: news.v1.NewsDetail.news:type_name -> news.v1.News
+	4,  // 10: news.v1.NewsDetail.comments:type_name -> news.v1.CommentNode
+	15, // 11: news.v1.Source.last_fetch:type_name -> google.protobuf.Timestamp
+	7,  // 12: news.v1.SourceList.items:type_name -> news.v1.Source
+	12, // 13: news.v1.NewsBatch.items:type_name -> news.v1.NewsBatch.ItemsEntry
+	13, // 14: news.v1.NewsBatchItem.news:type_name -> news.v1.News
+	11, // 15: news.v1.NewsBatchItem.error:type_name -> news.v1.BatchError
+	10, // 16: news.v1.NewsBatch.ItemsEntry.value:type_name -> news.v1.NewsBatchItem
+	17, // [17:17] is the sub-list for method output_type
+	17, // [17:17] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
+}
+
+func init() { file_news_v1_payloads_proto_init() }
+func file_news_v1_payloads_proto_init() {
+	if File_news_v1_payloads_proto != nil {
+		return
+	}
+	file_news_v1_news_proto_init()
+	file_news_v1_payloads_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_news_v1_payloads_proto_rawDesc), len(file_news_v1_payloads_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_news_v1_payloads_proto_goTypes,
+		DependencyIndexes: file_news_v1_payloads_proto_depIdxs,
+		MessageInfos:      file_news_v1_payloads_proto_msgTypes,
+	}.Build()
+	File_news_v1_payloads_proto = out.File
+	file_news_v1_payloads_proto_goTypes = nil
+	file_news_v1_payloads_proto_depIdxs = nil
+}
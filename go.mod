@@ -3,11 +3,27 @@ module apigateway
 go 1.24.2
 
 require (
-	github.com/Fau1con/kafkawrapper v0.0.0-20250930120434-2be0ca3c5dd2 // indirect
-	github.com/Fau1con/renderresponse v0.0.0-20251019110801-a7e73e4186f8 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
-	github.com/klauspost/compress v1.15.9 // indirect
+	github.com/Fau1con/kafkawrapper v0.0.0-20250930120434-2be0ca3c5dd2
+	github.com/Fau1con/renderresponse v0.0.0-20251019110801-a7e73e4186f8
+	github.com/andybalholm/brotli v1.1.1
+	github.com/coder/websocket v1.8.14
+	github.com/graph-gophers/dataloader/v7 v7.1.0
+	github.com/graph-gophers/graphql-go v1.9.0
+	github.com/klauspost/compress v1.15.9
+	github.com/segmentio/kafka-go v0.4.49
+	github.com/swaggo/files v1.0.1
+	github.com/vektah/gqlparser/v2 v2.5.31
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	google.golang.org/grpc v1.80.0
+	google.golang.org/protobuf v1.36.11
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
 	github.com/pierrec/lz4/v4 v4.1.15 // indirect
-	github.com/segmentio/kafka-go v0.4.49 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/net v0.49.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20260120221211-b8f7ae30c516 // indirect
 )
@@ -1,11 +1,21 @@
 package api
 
 import (
+	"apigateway/internal/cache"
+	"apigateway/internal/censor"
 	"apigateway/internal/models"
+	"apigateway/internal/openapi"
+	"apigateway/internal/pagination"
+	"apigateway/internal/problem"
+	"apigateway/internal/search"
+	"apigateway/internal/sources"
+	"apigateway/internal/stream"
+	gql "apigateway/internal/transport/graphql"
 	transport "apigateway/internal/transport/http"
 	"context"
 	"log/slog"
 	"net/http"
+	"time"
 
 	kfk "github.com/Fau1con/kafkawrapper"
 )
@@ -19,6 +29,24 @@ type Api struct {
 	commentsConsumer *kfk.Consumer
 	filteredContent  *kfk.Consumer
 	filterPublished  *kfk.Consumer
+	censor           *censor.Client
+	cursorSigner     *pagination.Signer
+	searchIndex      *search.Index
+	searchTimeout    time.Duration
+	sources          *sources.Registry
+	feedValidator    *sources.Validator
+	feedCache        *cache.TTL[transport.RenderedFeed]
+	newsStream       *stream.Broker[models.NewsFullDetailed]
+	streamHeartbeat  time.Duration
+	commentStream    *stream.Broker[models.Comment]
+	wsOrigins        []string
+	graphqlAllow     *gql.Allowlist
+	graphqlLimits    gql.Limits
+	routes           *openapi.Registry
+	validateRequests bool
+	v1Deprecation    transport.Deprecation
+	batchLimits      transport.BatchLimits
+	pages            *transport.Pages
 	responseChan     chan models.DetailedResponse
 	defaultLimit     int
 	ctx              context.Context
@@ -26,6 +54,11 @@ type Api struct {
 	topics           Topics
 }
 
+const (
+	feedTitle     = "GoNews"
+	feedCacheSize = 256
+)
+
 type Topics struct {
 	NewsInput     string
 	CommentsInput string
@@ -36,7 +69,13 @@ func New(
 	ctx context.Context, resp chan models.DetailedResponse,
 	newsProducer, commentProducer *kfk.Producer,
 	detailConsumer, listConsumer, commentsConsumer, filteredContent, filterPublished *kfk.Consumer,
-	log *slog.Logger, topics Topics, limit int,
+	cens *censor.Client, signer *pagination.Signer, idx *search.Index, searchTimeout time.Duration,
+	log *slog.Logger, topics Topics, limit int, reg *sources.Registry, validator *sources.Validator, feedCacheTTL time.Duration,
+	newsStream *stream.Broker[models.NewsFullDetailed], streamHeartbeat time.Duration,
+	commentStream *stream.Broker[models.Comment], wsOrigins []string,
+	graphqlAllow *gql.Allowlist, graphqlLimits gql.Limits,
+	validateRequests bool, v1Deprecation transport.Deprecation,
+	batchLimits transport.BatchLimits, pages *transport.Pages,
 ) *Api {
 	api := &Api{
 		mux:              http.NewServeMux(),
@@ -47,31 +86,40 @@ func New(
 		commentsConsumer: commentsConsumer,
 		filteredContent:  filteredContent,
 		filterPublished:  filterPublished,
+		censor:           cens,
+		cursorSigner:     signer,
+		searchIndex:      idx,
+		searchTimeout:    searchTimeout,
 		responseChan:     resp,
 		ctx:              ctx,
 		log:              log,
 		topics:           topics,
 		defaultLimit:     limit,
+		sources:          reg,
+		feedValidator:    validator,
+		feedCache:        cache.New[transport.RenderedFeed](feedCacheTTL, feedCacheSize),
+		newsStream:       newsStream,
+		streamHeartbeat:  streamHeartbeat,
+		commentStream:    commentStream,
+		wsOrigins:        wsOrigins,
+		graphqlAllow:     graphqlAllow,
+		graphqlLimits:    graphqlLimits,
+		routes:           openapi.NewRegistry(),
+		validateRequests: validateRequests,
+		v1Deprecation:    v1Deprecation,
+		batchLimits:      batchLimits,
+		pages:            pages,
 	}
 	api.registerRoutes()
 	return api
 }
 
-// registerRoutes навешивает HTTP-маршруты.
-func (a *Api) registerRoutes() {
-	a.mux.HandleFunc("/", transport.HandleRoot)
-	a.mux.HandleFunc("/newslist/", transport.HandleNewsList(a.ctx, a.listConsumer, a.newsProducer))
-	a.mux.HandleFunc("/newslist/filtered/", transport.HandleFilterContent(a.ctx, a.listConsumer, a.newsProducer))
-	a.mux.HandleFunc("/newslist/filtered/date", transport.HandleFilterDate(a.ctx, a.listConsumer, a.newsProducer))
-	a.mux.HandleFunc("/newsdetail", transport.HandleNewsDetail(a.ctx, a.listConsumer, a.commentsConsumer, a.newsProducer, a.commentProducer))
-	a.mux.HandleFunc("/comments/", transport.HandleCommentsByNews(a.ctx, a.commentsConsumer, a.commentProducer))
-	a.mux.HandleFunc("/addcomment/", transport.HandleAddComment(a.ctx, a.commentsConsumer, a.commentProducer))
-}
-
+// Router возвращает маршрутизатор с ответами 404 и 405 в формате problem+json.
+// Ошибка, отданная хендлером, завершает ответ (см. problem.Guard).
 func (a *Api) Router() http.Handler {
-	return a.mux
+	return problem.Guard(transport.RouteErrorsMiddleware(a.mux))
 }
 
 func (a *Api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	a.mux.ServeHTTP(w, r)
+	a.Router().ServeHTTP(w, r)
 }
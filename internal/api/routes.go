@@ -0,0 +1,569 @@
+package api
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"apigateway/internal/openapi"
+	gql "apigateway/internal/transport/graphql"
+	transport "apigateway/internal/transport/http"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	v1Prefix = "/api/v1"
+	v2Prefix = "/api/v2"
+	specPath = "/openapi.json"
+	docsPath = "/docs/"
+)
+
+// Общие параметры маршрутов.
+var (
+	pageParam  = openapi.Query("page", openapi.Integer(1, 0, 1), "Номер страницы")
+	limitParam = openapi.Query("limit", openapi.Integer(1, 100, 10), "Размер страницы")
+	newsIDPath = openapi.Path("id", openapi.Integer(1, 0, 0), "Идентификатор новости")
+
+	filterParams = []openapi.Param{
+		openapi.Query("category", openapi.Array(openapi.String("")), "Категории; через запятую или повторением, префикс - исключает"),
+		openapi.Query("author", openapi.Array(openapi.String("")), "Авторы; через запятую или повторением, префикс - исключает"),
+		openapi.Query("tags", openapi.Array(openapi.String("")), "Теги; через запятую или повторением, префикс - исключает"),
+		openapi.Query("source", openapi.Array(openapi.String("")), "Источники из /sources; префикс - исключает"),
+		openapi.Query("match", openapi.Enum(models.MatchAny, models.MatchAny, models.MatchAll), "Совпадение включаемых тегов: любой или все"),
+		openapi.Query("sort", openapi.Enum(models.SortPublishedDesc, models.SortPublishedDesc, models.SortPublishedAsc, models.SortTitleAsc, models.SortTitleDesc), "Порядок"),
+	}
+	dateParams = []openapi.Param{
+		openapi.Query("from", openapi.String(""), "Начало периода: RFC 3339 или YYYY-MM-DD"),
+		openapi.Query("to", openapi.String(""), "Конец периода: RFC 3339 или YYYY-MM-DD (дата включает весь день)"),
+		openapi.Query("last", openapi.String(""), "Относительный период до текущего момента: 90m, 24h, 7d, 2w"),
+		openapi.Query("date", openapi.String(""), "Один день YYYY-MM-DD"),
+		openapi.Query("tz", openapi.String(""), "Часовой пояс IANA для дат без смещения, по умолчанию UTC"),
+	}
+	threadParams = []openapi.Param{
+		openapi.Query("parent_id", openapi.Integer(1, 0, 0), "Ветка ответов на комментарий"),
+		openapi.Query("view", openapi.Enum("tree", "tree", "flat"), "Дерево или плоский список"),
+		openapi.Query("include_censored", openapi.Boolean(), "Показать скрытые модерацией (только модераторы)"),
+		pageParam,
+		limitParam,
+	}
+	streamParams = params([]openapi.Param{
+		openapi.Query("source", openapi.Array(openapi.String("")), "Источники"),
+		openapi.Query("author", openapi.Array(openapi.String("")), "Авторы"),
+		openapi.Query("tags", openapi.Array(openapi.String("")), "Теги"),
+		openapi.Query("match", openapi.Enum(models.MatchAny, models.MatchAny, models.MatchAll), "Совпадение тегов"),
+		openapi.Query("last_event_id", openapi.String(""), "Альтернатива заголовку Last-Event-ID"),
+	}, newsFieldParams)
+	searchParams = params([]openapi.Param{
+		openapi.Query("q", &openapi.Schema{Type: "string", MaxLength: intPtr(256)}, "Поисковый запрос").Require(),
+		pageParam,
+		limitParam,
+	}, fieldParams(fields.Search))
+	newsFieldParams = fieldParams(fields.News)
+	newsListParams  = fieldParams(fields.NewsList)
+)
+
+// fieldParams - выборка полей ресурса (fields, view).
+func fieldParams(res fields.Resource) []openapi.Param {
+	ps := []openapi.Param{
+		openapi.Query("fields", openapi.Array(openapi.String("")), "Поля через запятую: "+strings.Join(res.Fields, ", ")+"; news_ id возвращается всегда"),
+		openapi.Query("view", openapi.Enum("full", "full", "short"), "short - только title и description; не сочетается с fields"),
+	}
+	if len(res.Includes) > 0 {
+		ps = append(ps, openapi.Query("include", openapi.Array(openapi.String("")),
+			"Дополнительные данные: "+strings.Join(res.Includes, ", ")+". Если сервис комментариев не ответил, comment_count не заполняется и ответ получает заголовок X-Degraded"))
+	}
+	return ps
+}
+
+func params(groups ...[]openapi.Param) []openapi.Param {
+	var all []openapi.Param
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	return all
+}
+
+// registerRoutes описывает HTTP-маршруты в реестре и навешивает их на mux.
+func (a *Api) registerRoutes() {
+	a.registerV1()
+	a.registerV2()
+	a.registerShared()
+	a.routes.Mount(a.mux, a.validateRequests)
+}
+
+// handleV1 регистрирует маршрут /api/v1 и прежний путь без префикса для
+// существующих клиентов. Ответы помечаются устаревшими со ссылкой на successor.
+func (a *Api) handleV1(route openapi.Route, successor func(r *http.Request) string) {
+	route.Aliases = append(route.Aliases, route.Path)
+	route.Path = v1Prefix + route.Path
+	route.Middleware = append(route.Middleware, transport.DeprecationMiddleware(a.v1Deprecation, successor))
+	for i := range route.Ops {
+		route.Ops[i].Deprecated = true
+		route.Ops[i].Tags = append(route.Ops[i].Tags, "v1")
+	}
+	a.routes.Handle(route)
+}
+
+// registerV1 - исходные маршруты шлюза.
+func (a *Api) registerV1() {
+	a.handleV1(openapi.Route{
+		Path:    "/newslist/",
+		Handler: transport.HandleNewsList(a.ctx, a.listConsumer, a.newsProducer, a.commentsConsumer, a.commentProducer, a.cursorSigner, a.searchIndex, a.sources),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "Лента новостей",
+			Description: "Курсорная пагинация (cursor, limit); page поддерживается для совместимости и не сочетается с cursor, размер страницы - limit или n.",
+			Tags:        []string{"news"},
+			Params: params([]openapi.Param{
+				openapi.Query("cursor", openapi.String(""), "Курсор из полей next/prev предыдущего ответа"),
+				limitParam,
+				pageParam,
+				openapi.Query("n", openapi.Integer(1, 100, 10), "Размер страницы в режиме page, синоним limit"),
+				openapi.Query("source", openapi.Array(openapi.String("")), "Источники из /sources"),
+			}, newsListParams),
+			Response: models.ListEnvelope[models.NewsFullDetailed]{},
+			Proto:    "news.v1.NewsPage",
+			Errors:   []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	}, successor(v2Prefix+"/news"))
+	a.handleV1(openapi.Route{
+		Path:    "/newslist/filtered/",
+		Handler: transport.HandleFilterContent(a.ctx, a.listConsumer, a.newsProducer, a.commentsConsumer, a.commentProducer, a.searchIndex, a.sources),
+		Ops: []openapi.Op{{
+			Method:   http.MethodGet,
+			Summary:  "Лента по фильтру",
+			Tags:     []string{"news"},
+			Params:   params(filterParams, dateParams, []openapi.Param{pageParam, limitParam}, newsListParams),
+			Response: models.ListEnvelope[models.NewsFullDetailed]{},
+			Proto:    "news.v1.NewsPage",
+			Errors:   []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	}, successor(v2Prefix+"/news"))
+	a.handleV1(openapi.Route{
+		Path:    "/newslist/filtered/date",
+		Handler: transport.HandleFilterDate(a.ctx, a.listConsumer, a.newsProducer, a.commentsConsumer, a.commentProducer, a.searchIndex),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "Лента за период",
+			Description: "Нужен один из вариантов: from/to, last или date.",
+			Tags:        []string{"news"},
+			Params:      params(dateParams, []openapi.Param{pageParam, limitParam}, newsListParams),
+			Response:    models.ListEnvelope[models.NewsFullDetailed]{},
+			Proto:       "news.v1.NewsPage",
+			Errors:      []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	}, successor(v2Prefix+"/news"))
+	a.handleV1(openapi.Route{
+		Path:    "/news/search",
+		Handler: transport.HandleSearch(a.ctx, a.listConsumer, a.newsProducer, a.searchIndex, a.searchTimeout),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "Полнотекстовый поиск",
+			Description: "Поддерживаются фразы в кавычках, AND, OR, NOT, -слово и скобки. Заголовок X-Search-Source: backend или local.",
+			Tags:        []string{"news"},
+			Params:      searchParams,
+			Response:    models.ListEnvelope[models.SearchHit]{},
+			Proto:       "news.v1.SearchPage",
+			Errors:      []int{http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	}, successor(v2Prefix+"/news/search"))
+	a.handleV1(openapi.Route{
+		Path:    "/news/stream",
+		Handler: transport.HandleNewsStream(a.newsStream, a.sources, a.streamHeartbeat),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "SSE-поток новых новостей",
+			Description: "События news с id для возобновления по заголовку Last-Event-ID.",
+			Tags:        []string{"news"},
+			Params:      streamParams,
+			ContentType: "text/event-stream",
+			Response:    openapi.String(""),
+			Errors:      []int{http.StatusServiceUnavailable},
+		}},
+	}, successor(v2Prefix+"/news/stream"))
+	a.handleV1(openapi.Route{
+		Path:    "/sources",
+		Handler: transport.HandleSources(a.ctx, a.listConsumer, a.newsProducer, a.sources),
+		Ops: []openapi.Op{{
+			Method:   http.MethodGet,
+			Summary:  "Каталог источников",
+			Tags:     []string{"sources"},
+			Response: []models.Source{},
+			Proto:    "news.v1.SourceList",
+		}},
+	}, successor(v2Prefix+"/sources"))
+	a.handleV1(openapi.Route{
+		Path:    "/admin/feeds",
+		Handler: transport.HandleAdminFeeds(a.ctx, a.newsProducer, a.topics.NewsInput, a.sources, a.feedValidator),
+		Ops: []openapi.Op{
+			{
+				Method:   http.MethodPost,
+				Summary:  "Добавить ленту",
+				Tags:     []string{"sources"},
+				Body:     models.AddFeedRequest{},
+				Response: models.Source{},
+				Proto:    "news.v1.Source",
+				Status:   http.StatusCreated,
+				Auth:     true,
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusBadGateway, http.StatusGatewayTimeout},
+			},
+			{
+				Method:   http.MethodDelete,
+				Summary:  "Удалить ленту",
+				Tags:     []string{"sources"},
+				Params:   []openapi.Param{openapi.Query("name", openapi.String(""), "Имя ленты").Require()},
+				Response: models.Source{},
+				Proto:    "news.v1.Source",
+				Auth:     true,
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusBadGateway, http.StatusGatewayTimeout},
+			},
+		},
+	}, func(r *http.Request) string {
+		if name := r.URL.Query().Get("name"); name != "" {
+			return v2Prefix + "/sources/" + url.PathEscape(name)
+		}
+		return v2Prefix + "/sources"
+	})
+	a.handleV1(openapi.Route{
+		Path:    "/newsdetail",
+		Handler: transport.HandleNewsDetail(a.ctx, a.listConsumer, a.commentsConsumer, a.newsProducer, a.commentProducer),
+		Ops: []openapi.Op{{
+			Method:   http.MethodGet,
+			Summary:  "Новость с деревом комментариев",
+			Tags:     []string{"news"},
+			Params:   params([]openapi.Param{openapi.Query("id", openapi.Integer(1, 0, 0), "Идентификатор новости").Require()}, newsFieldParams),
+			Response: models.FinalResponse{},
+			Proto:    "news.v1.NewsDetail",
+			Errors:   []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	}, newsSuccessor("id", ""))
+	a.handleV1(openapi.Route{
+		Path:    "/comments/",
+		Handler: transport.HandleCommentsByNews(a.ctx, a.commentsConsumer, a.commentProducer),
+		Ops: []openapi.Op{{
+			Method:   http.MethodGet,
+			Summary:  "Комментарии к новости",
+			Tags:     []string{"comments"},
+			Params:   params([]openapi.Param{openapi.Query("newsID", openapi.Integer(1, 0, 0), "Идентификатор новости").Require()}, threadParams),
+			Response: models.CommentThread{},
+			Proto:    "news.v1.CommentThread",
+			Errors:   []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	}, newsSuccessor("newsID", "/comments"))
+	a.handleV1(openapi.Route{
+		Path:    "/addcomment/",
+		Handler: transport.HandleAddComment(a.ctx, a.commentsConsumer, a.commentProducer, a.censor),
+		Ops: []openapi.Op{{
+			Method:      http.MethodPost,
+			Summary:     "Добавить комментарий",
+			Description: "202, если цензор недоступен и комментарий ожидает модерации.",
+			Tags:        []string{"comments"},
+			Params: []openapi.Param{
+				openapi.Query("news_id", openapi.Integer(1, 0, 0), "Идентификатор новости"),
+				openapi.Query("parent_id", openapi.Integer(1, 0, 0), "Комментарий, на который отвечают"),
+				openapi.Query("comment", &openapi.Schema{Type: "string", MaxLength: intPtr(backend.MaxCommentLength)}, "Текст комментария").Require(),
+			},
+			Response: []byte{},
+			Status:   http.StatusCreated,
+			Errors:   []int{http.StatusAccepted, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusServiceUnavailable},
+		}},
+	}, newsSuccessor("news_id", "/comments"))
+}
+
+// registerV2 - REST-ресурсы второй версии API.
+func (a *Api) registerV2() {
+	a.routes.Handle(openapi.Route{
+		Path: v2Prefix + "/news",
+		Handler: transport.HandleNewsCollection(
+			transport.HandleNewsList(a.ctx, a.listConsumer, a.newsProducer, a.commentsConsumer, a.commentProducer, a.cursorSigner, a.searchIndex, a.sources),
+			transport.HandleFilterContent(a.ctx, a.listConsumer, a.newsProducer, a.commentsConsumer, a.commentProducer, a.searchIndex, a.sources),
+		),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "Лента новостей",
+			Description: "Без фильтров - курсорная пагинация (cursor, limit). Параметры фильтра или периода, кроме source, переключают на постраничную выборку (page, limit).",
+			Tags:        []string{"news"},
+			Params: params(
+				[]openapi.Param{openapi.Query("cursor", openapi.String(""), "Курсор из полей next/prev предыдущего ответа"), limitParam, pageParam},
+				filterParams, dateParams, newsListParams,
+			),
+			Response: models.ListEnvelope[models.NewsFullDetailed]{},
+			Proto:    "news.v1.NewsPage",
+			Errors:   []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	})
+	a.routes.Handle(openapi.Route{
+		Path:    v2Prefix + "/news/search",
+		Handler: transport.HandleSearch(a.ctx, a.listConsumer, a.newsProducer, a.searchIndex, a.searchTimeout),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "Полнотекстовый поиск",
+			Description: "Поддерживаются фразы в кавычках, AND, OR, NOT, -слово и скобки. Заголовок X-Search-Source: backend или local.",
+			Tags:        []string{"news"},
+			Params:      searchParams,
+			Response:    models.ListEnvelope[models.SearchHit]{},
+			Proto:       "news.v1.SearchPage",
+			Errors:      []int{http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	})
+	a.routes.Handle(openapi.Route{
+		Path:    v2Prefix + "/news/stream",
+		Handler: transport.HandleNewsStream(a.newsStream, a.sources, a.streamHeartbeat),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "SSE-поток новых новостей",
+			Description: "События news с id для возобновления по заголовку Last-Event-ID.",
+			Tags:        []string{"news"},
+			Params:      streamParams,
+			ContentType: "text/event-stream",
+			Response:    openapi.String(""),
+			Errors:      []int{http.StatusServiceUnavailable},
+		}},
+	})
+	a.routes.Handle(openapi.Route{
+		Path:    v2Prefix + "/news/batch",
+		Handler: transport.HandleNewsBatch(a.ctx, a.listConsumer, a.newsProducer, a.searchIndex, a.batchLimits),
+		Ops: []openapi.Op{{
+			Method:      http.MethodPost,
+			Summary:     "Новости по списку id",
+			Description: "Ответ - объект id -> новость или ошибка по этому id; повторяющиеся id объединяются.",
+			Tags:        []string{"news"},
+			Params:      newsFieldParams,
+			Body:        models.NewsBatchRequest{},
+			Response:    models.NewsBatch{},
+			Proto:       "news.v1.NewsBatch",
+			Errors:      []int{http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	})
+	a.routes.Handle(openapi.Route{
+		Path:    v2Prefix + "/pages/{name}",
+		Handler: transport.HandlePage(a.ctx, a.listConsumer, a.newsProducer, a.commentsConsumer, a.commentProducer, a.searchIndex, a.sources, a.pages),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "Составная страница",
+			Description: "Части страницы из конфигурации (pages) запрашиваются параллельно. Ошибки частей - в errors, такой ответ помечается partial и не кэшируется.",
+			Tags:        []string{"pages"},
+			Params:      []openapi.Param{openapi.Path("name", openapi.String(""), "Имя страницы, например home")},
+			Response:    models.CompositePage{},
+			Errors:      []int{http.StatusNotFound, http.StatusBadGateway},
+		}},
+	})
+	a.routes.Handle(openapi.Route{
+		Path:    v2Prefix + "/news/{id}",
+		Handler: transport.HandleNewsItem(a.ctx, a.listConsumer, a.newsProducer, a.searchIndex),
+		Ops: []openapi.Op{{
+			Method:   http.MethodGet,
+			Summary:  "Новость",
+			Tags:     []string{"news"},
+			Params:   params([]openapi.Param{newsIDPath}, newsFieldParams),
+			Response: models.NewsFullDetailed{},
+			Proto:    "news.v1.News",
+			Errors:   []int{http.StatusNotFound, http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	})
+	a.routes.Handle(openapi.Route{
+		Path: v2Prefix + "/news/{id}/comments",
+		Ops: []openapi.Op{
+			{
+				Method:   http.MethodGet,
+				Handler:  transport.HandleNewsComments(a.ctx, a.commentsConsumer, a.commentProducer),
+				Summary:  "Комментарии к новости",
+				Tags:     []string{"comments"},
+				Params:   params([]openapi.Param{newsIDPath}, threadParams),
+				Response: models.CommentThread{},
+				Proto:    "news.v1.CommentThread",
+				Errors:   []int{http.StatusForbidden, http.StatusNotFound, http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout},
+			},
+			{
+				Method:      http.MethodPost,
+				Handler:     transport.HandleCreateComment(a.ctx, a.commentsConsumer, a.commentProducer, a.censor),
+				Summary:     "Добавить комментарий",
+				Description: "202, если цензор недоступен и комментарий ожидает модерации.",
+				Tags:        []string{"comments"},
+				Params:      []openapi.Param{newsIDPath},
+				Body:        models.NewCommentRequest{},
+				Response:    []byte{},
+				Status:      http.StatusCreated,
+				Errors:      []int{http.StatusAccepted, http.StatusUnprocessableEntity, http.StatusInternalServerError, http.StatusServiceUnavailable},
+			},
+		},
+	})
+	a.routes.Handle(openapi.Route{
+		Path: v2Prefix + "/sources",
+		Ops: []openapi.Op{
+			{
+				Method:   http.MethodGet,
+				Handler:  transport.HandleSources(a.ctx, a.listConsumer, a.newsProducer, a.sources),
+				Summary:  "Каталог источников",
+				Tags:     []string{"sources"},
+				Response: []models.Source{},
+				Proto:    "news.v1.SourceList",
+			},
+			{
+				Method:   http.MethodPost,
+				Handler:  transport.HandleAdminFeeds(a.ctx, a.newsProducer, a.topics.NewsInput, a.sources, a.feedValidator),
+				Summary:  "Добавить ленту",
+				Tags:     []string{"sources"},
+				Body:     models.AddFeedRequest{},
+				Response: models.Source{},
+				Proto:    "news.v1.Source",
+				Status:   http.StatusCreated,
+				Auth:     true,
+				Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusUnprocessableEntity, http.StatusBadGateway, http.StatusGatewayTimeout},
+			},
+		},
+	})
+	a.routes.Handle(openapi.Route{
+		Path:    v2Prefix + "/sources/{name}",
+		Handler: transport.HandleAdminFeeds(a.ctx, a.newsProducer, a.topics.NewsInput, a.sources, a.feedValidator),
+		Ops: []openapi.Op{{
+			Method:   http.MethodDelete,
+			Summary:  "Удалить ленту",
+			Tags:     []string{"sources"},
+			Params:   []openapi.Param{openapi.Path("name", openapi.String(""), "Имя ленты")},
+			Response: models.Source{},
+			Proto:    "news.v1.Source",
+			Auth:     true,
+			Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusBadGateway, http.StatusGatewayTimeout},
+		}},
+	})
+}
+
+// registerShared - маршруты вне версий API: ленты RSS/Atom с постоянными
+// адресами, WebSocket, GraphQL и документация.
+func (a *Api) registerShared() {
+	feeds := []openapi.Param{openapi.Query("limit", openapi.Integer(1, 100, 20), "Количество новостей в ленте")}
+
+	a.routes.Handle(openapi.Route{
+		Path:    "/",
+		Handler: http.HandlerFunc(transport.HandleRoot),
+		Ops:     []openapi.Op{{Method: http.MethodGet, Summary: "Проверка доступности", ContentType: "text/plain", Response: openapi.String("")}},
+	})
+	for path, format := range map[string]string{"/feed.rss": transport.FeedFormatRSS, "/feed.atom": transport.FeedFormatAtom} {
+		contentType := "application/rss+xml"
+		if format == transport.FeedFormatAtom {
+			contentType = "application/atom+xml"
+		}
+		ops := make([]openapi.Op, 0, 2)
+		for _, method := range []string{http.MethodGet, http.MethodHead} {
+			ops = append(ops, openapi.Op{
+				Method:      method,
+				Summary:     "Лента GoNews в формате " + format,
+				Description: "Параметры фильтра как у /newslist/filtered/. Поддерживаются If-None-Match и If-Modified-Since.",
+				Tags:        []string{"feeds"},
+				Params:      params(feeds, filterParams, dateParams),
+				ContentType: contentType,
+				Response:    openapi.String(""),
+				Errors:      []int{http.StatusBadGateway, http.StatusGatewayTimeout},
+			})
+		}
+		a.routes.Handle(openapi.Route{
+			Path:    path,
+			Handler: transport.HandleFeed(a.ctx, a.listConsumer, a.newsProducer, a.searchIndex, a.sources, a.feedCache, format, feedTitle),
+			Ops:     ops,
+		})
+	}
+	a.routes.Handle(openapi.Route{
+		Path:    "/ws/comments",
+		Handler: transport.HandleCommentsWS(a.ctx, a.commentsConsumer, a.commentProducer, a.censor, a.commentStream, a.wsOrigins),
+		Ops: []openapi.Op{{
+			Method:      http.MethodGet,
+			Summary:     "WebSocket-канал комментариев",
+			Description: "Сервер шлет события comment, клиент - add_comment; медленные клиенты отключаются с кодом 1013.",
+			Tags:        []string{"comments"},
+			Params:      []openapi.Param{openapi.Query("news_id", openapi.Integer(1, 0, 0), "Идентификатор новости").Require()},
+			Status:      http.StatusSwitchingProtocols,
+			Errors:      []int{http.StatusServiceUnavailable},
+		}},
+	})
+
+	// GraphQL подключается, только если загружен список разрешенных запросов
+	if a.graphqlAllow != nil {
+		resolver := gql.NewResolver(a.listConsumer, a.commentsConsumer, a.newsProducer, a.commentProducer, a.censor, a.searchIndex, a.sources)
+		handler, err := gql.Handler(resolver, a.graphqlAllow, a.graphqlLimits)
+		if err != nil {
+			a.log.Error("graphql endpoint is disabled", "error", err)
+		} else {
+			a.routes.Handle(openapi.Route{
+				Path:    "/graphql",
+				Handler: handler,
+				Ops: []openapi.Op{
+					{
+						Method:      http.MethodGet,
+						Summary:     "GraphQL-запрос",
+						Description: "Только query-операции; мутации принимаются через POST.",
+						Tags:        []string{"graphql"},
+						Params: []openapi.Param{
+							openapi.Query("query", openapi.String(""), "Текст запроса"),
+							openapi.Query("operationName", openapi.String(""), "Имя операции"),
+							openapi.Query("variables", openapi.String(""), "Переменные, JSON"),
+							openapi.Query("extensions", openapi.String(""), "Расширения (persistedQuery), JSON"),
+						},
+						Response: graphqlResponse{},
+						Raw:      true,
+						Errors:   []int{http.StatusForbidden, http.StatusNotFound, http.StatusMethodNotAllowed},
+					},
+					{
+						Method:   http.MethodPost,
+						Summary:  "GraphQL",
+						Tags:     []string{"graphql"},
+						Body:     graphqlRequest{},
+						Response: graphqlResponse{},
+						Raw:      true,
+						Errors:   []int{http.StatusForbidden, http.StatusNotFound},
+					},
+				},
+			})
+		}
+	}
+
+	a.routes.Handle(openapi.Route{
+		Path:    specPath,
+		Handler: openapi.HandleSpec(a.routes, openapi.Info{Title: "GoNews API Gateway", Version: "2.0.0"}),
+		Ops:     []openapi.Op{{Method: http.MethodGet, Summary: "Документ OpenAPI", Tags: []string{"docs"}, Response: map[string]any{}, Raw: true}},
+	})
+	a.routes.Handle(openapi.Route{
+		Path:    docsPath,
+		Prefix:  true,
+		Handler: openapi.HandleDocs(docsPath, specPath),
+		Ops:     []openapi.Op{{Method: http.MethodGet, Summary: "Swagger UI", Tags: []string{"docs"}, ContentType: "text/html", Response: openapi.String("")}},
+	})
+}
+
+// successor - ссылка на постоянный адрес замены в /api/v2.
+func successor(path string) func(r *http.Request) string {
+	return func(*http.Request) string {
+		return path
+	}
+}
+
+// newsSuccessor - ссылка на ресурс новости /api/v2/news/{id}<suffix>;
+// идентификатор берется из query-параметра param.
+func newsSuccessor(param, suffix string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		id := r.URL.Query().Get(param)
+		if id == "" {
+			return ""
+		}
+		return v2Prefix + "/news/" + url.PathEscape(id) + suffix
+	}
+}
+
+// graphqlRequest - тело запроса /graphql для документации.
+type graphqlRequest struct {
+	Query         string         `json:"query,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Extensions    map[string]any `json:"extensions,omitempty"`
+}
+
+// graphqlResponse - ответ /graphql для документации.
+type graphqlResponse struct {
+	Data   map[string]any   `json:"data,omitempty"`
+	Errors []map[string]any `json:"errors,omitempty"`
+}
+
+func intPtr(v int) *int {
+	return &v
+}
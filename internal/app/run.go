@@ -2,18 +2,32 @@ package app
 
 import (
 	"apigateway/internal/api"
+	"apigateway/internal/censor"
 	conf "apigateway/internal/infrastructure/config"
+	"apigateway/internal/infrastructure/events"
 	"apigateway/internal/models"
+	"apigateway/internal/pagination"
+	"apigateway/internal/search"
+	"apigateway/internal/sources"
+	"apigateway/internal/stream"
+	gql "apigateway/internal/transport/graphql"
+	grpctransport "apigateway/internal/transport/grpc"
 	transport "apigateway/internal/transport/http"
 	"context"
 	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+
 	kfk "github.com/Fau1con/kafkawrapper"
 )
 
@@ -24,7 +38,7 @@ func Run(configPath string) error {
 
 	cfg, err := conf.LoadConfig(configPath)
 	if err != nil {
-		log.Printf("Failed to load config from config file: %w", err)
+		log.Printf("Failed to load config from config file: %v", err)
 		return fmt.Errorf("failed to load config from config file: %w", err)
 	}
 
@@ -33,58 +47,123 @@ func Run(configPath string) error {
 
 	responseChan := make(chan models.DetailedResponse, 2)
 
-	// Инициализация Kafka клиентов
-	newsProducer, err := kfk.NewProducer([]string{"localhost:9093"})
+	// Инициализация Kafka клиентов. Все клиенты, включая читателей событий,
+	// подключаются к брокерам kafka.brokers.
+	brokers := cfg.Kafka.Brokers
+	if len(brokers) == 0 {
+		return fmt.Errorf("kafka.brokers is not configured")
+	}
+	newsProducer, err := kfk.NewProducer(brokers)
 	if err != nil {
 		log.Printf("failed to create news producer: %v\n", err)
 		return err
 	}
 
-	commentsProducer, err := kfk.NewProducer([]string{"localhost:9093"})
+	commentsProducer, err := kfk.NewProducer(brokers)
 	if err != nil {
 		log.Printf("failed to create comment producer: %v\n", err)
 		return err
 	}
 
-	detailConsumer, err := kfk.NewConsumer([]string{"localhost:9093"}, "newsdetail")
+	detailConsumer, err := kfk.NewConsumer(brokers, "newsdetail")
 	if err != nil {
 		log.Printf("failet to create detail consumer: %v\n", err)
 		return err
 	}
 
-	listConsumer, err := kfk.NewConsumer([]string{"localhost:9093"}, "newslist")
+	listConsumer, err := kfk.NewConsumer(brokers, "newslist")
 	if err != nil {
 		log.Printf("failed to create list consumer: %v\n", err)
 		return err
 	}
 
-	filterContentConsumer, err := kfk.NewConsumer([]string{"localhost:9093"}, "filtered_content")
+	filterContentConsumer, err := kfk.NewConsumer(brokers, "filtered_content")
 	if err != nil {
 		log.Printf("failed to create filter content consumer: %v\n", err)
 		return err
 	}
 
-	filterPublishedConsumer, err := kfk.NewConsumer([]string{"localhost:9093"}, "filter_published")
+	filterPublishedConsumer, err := kfk.NewConsumer(brokers, "filter_published")
 	if err != nil {
 		log.Printf("failed to create filter published consumer: %v\n", err)
 		return err
 	}
 
-	commentsConsumer, err := kfk.NewConsumer([]string{"localhost:9093"}, "comments")
+	commentsConsumer, err := kfk.NewConsumer(brokers, "comments")
 	if err != nil {
 		log.Printf("failed to create comment consumer: %v\n", err)
 		return err
 	}
 
+	var cens *censor.Client
+	if cfg.Censor.Enabled {
+		baseURL, ok := cfg.GetRouteURL(cfg.Censor.Route)
+		if !ok {
+			return fmt.Errorf("censor route %q is not configured", cfg.Censor.Route)
+		}
+		cens = censor.New(baseURL, cfg.Censor.Path, cfg.GetCensorTimeout(), censor.Policy(cfg.Censor.OnTimeout))
+	}
+
+	signer, err := pagination.NewSigner(cfg.App.CursorSecret)
+	if err != nil {
+		return err
+	}
+
+	var searchIndex *search.Index
+	if cfg.Search.LocalIndex {
+		searchIndex = search.NewIndex(cfg.Search.MaxDocuments)
+	}
+
 	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelDebug,
 	}))
 
+	newsStream := stream.NewBroker(transport.NewsEventID, cfg.Stream.ReplaySize, cfg.Stream.MaxConnections, cfg.Stream.ClientBuffer)
+	// Поток SSE читает топик в собственной группе экземпляра, чтобы не забирать
+	// ответы у запросов через filterPublishedConsumer и получать все события.
+	newsEvents := events.NewReader(brokers, cfg.Kafka.Topics.FilterPublished, cfg.Kafka.ConsumerGroups["api_gateway"]+"-news")
+	defer newsEvents.Close()
+	go stream.Pump(ctxMain, newsEvents, newsStream, transport.DecodePublishedNews, log)
+
+	commentEvents := events.NewReader(brokers, cfg.Kafka.Topics.Comments, cfg.Kafka.ConsumerGroups["api_gateway"])
+	defer commentEvents.Close()
+	commentStream := stream.NewBroker(transport.CommentEventID, 0, cfg.WS.MaxConnections, cfg.WS.ClientBuffer)
+	go stream.Pump(ctxMain, commentEvents, commentStream, transport.DecodeCommentEvents, log)
+
+	var feedStore *sources.Store
+	if cfg.Feeds.StorePath != "" {
+		feedStore = sources.NewStore(cfg.Feeds.StorePath)
+	}
+	sourceRegistry, err := sources.NewRegistry(feedSources(cfg.App.FeedURLs), feedStore)
+	if err != nil {
+		return err
+	}
+	go sourceRegistry.WatchConfig(ctxMain, configPath, cfg.GetSourcesReloadInterval(), loadFeedSources, log)
+
 	topics := api.Topics{
 		NewsInput:     cfg.Kafka.Topics.NewsInput,
 		CommentsInput: cfg.Kafka.Topics.CommentsInput,
 		AddComments:   cfg.Kafka.Topics.AddComments,
 	}
+	v1Since, v1Sunset, err := cfg.GetV1Deprecation()
+	if err != nil {
+		return err
+	}
+
+	pages, err := transport.NewPages(pageSpecs(cfg.Pages))
+	if err != nil {
+		return err
+	}
+
+	var graphqlAllow *gql.Allowlist
+	if cfg.GraphQL.Enabled {
+		graphqlAllow, err = gql.LoadAllowlist(cfg.GraphQL.PersistedQueriesDir, cfg.GraphQL.AllowArbitraryQueries)
+		if err != nil {
+			return err
+		}
+		log.Info("GraphQL persisted queries loaded", "count", graphqlAllow.Len())
+	}
+
 	// Создание API и настройка middleware
 	apiInstance := api.New(
 		ctxMain,
@@ -96,14 +175,35 @@ func Run(configPath string) error {
 		filterContentConsumer,
 		filterPublishedConsumer,
 		commentsConsumer,
+		cens,
+		signer,
+		searchIndex,
+		cfg.GetSearchTimeout(),
 		log,
 		topics,
 		cfg.App.DefaultNewsLimit,
+		sourceRegistry,
+		sources.NewValidator(cfg.Feeds.ValidateFetch),
+		cfg.GetFeedCacheTTL(),
+		newsStream,
+		cfg.GetStreamHeartbeat(),
+		commentStream,
+		cfg.WS.AllowedOrigins,
+		graphqlAllow,
+		gql.Limits{MaxDepth: cfg.GraphQL.MaxDepth, MaxComplexity: cfg.GraphQL.MaxComplexity},
+		cfg.OpenAPI.ValidateRequests,
+		transport.Deprecation{Since: v1Since, Sunset: v1Sunset},
+		transport.BatchLimits{MaxIDs: cfg.Batch.MaxIDs},
+		pages,
 	)
 
 	var handler http.Handler = apiInstance.Router()
+	handler = transport.AuthMiddleware(cfg.GetAuthTokens())(handler)
 	handler = transport.RequestIDMiddleware(handler)
 	handler = transport.CORSMiddleware()(handler)
+	if cfg.Compression.Enabled {
+		handler = transport.CompressionMiddleware(cfg.Compression.MinSize)(handler)
+	}
 	handler = transport.LoggingMiddleware(log)(handler)
 
 	log.Info(
@@ -124,13 +224,78 @@ func Run(configPath string) error {
 		}
 	}()
 
+	var grpcServer *grpc.Server
+	var grpcHealth *health.Server
+	if cfg.GRPC.Enabled {
+		grpcAddr := net.JoinHostPort(cfg.GRPC.Host, strconv.Itoa(cfg.GRPC.Port))
+		lis, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen gRPC address: %w", err)
+		}
+		grpcServer, grpcHealth = grpctransport.Register(
+			grpctransport.NewServer(listConsumer, commentsConsumer, newsProducer, commentsProducer, cens, searchIndex, sourceRegistry, newsStream),
+			cfg.GetAuthTokens(),
+		)
+		log.Info("Starting gRPC server at:", slog.Any("address", grpcAddr))
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Error("gRPC server error", "error", err)
+			}
+		}()
+	}
+
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 	log.Info("Shutdown server...")
+	if grpcServer != nil {
+		grpcHealth.Shutdown()
+		grpcServer.GracefulStop()
+	}
 	ctxShutDown, cancelShutdown := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancelShutdown()
 
 	return server.Shutdown(ctxShutDown)
 }
+
+// feedSources преобразует feed_urls конфигурации в источники новостей.
+func feedSources(feeds []conf.FeedURL) []models.Source {
+	list := make([]models.Source, 0, len(feeds))
+	for _, f := range feeds {
+		list = append(list, models.Source{Name: f.Name, URL: f.URL})
+	}
+	return list
+}
+
+// pageSpecs переводит описание составных страниц из конфигурации.
+func pageSpecs(pages []conf.PageConfig) []transport.Page {
+	specs := make([]transport.Page, 0, len(pages))
+	for _, page := range pages {
+		spec := transport.Page{Name: page.Name, CacheTTL: time.Duration(page.CacheTTL) * time.Second}
+		for _, part := range page.Parts {
+			params := make(url.Values, len(part.Params))
+			for k, v := range part.Params {
+				params.Set(k, v)
+			}
+			spec.Parts = append(spec.Parts, transport.PagePart{
+				Name:    part.Name,
+				Kind:    part.Kind,
+				Of:      part.Of,
+				Timeout: time.Duration(part.TimeoutMs) * time.Millisecond,
+				Params:  params,
+			})
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// loadFeedSources перечитывает список источников из файла конфигурации.
+func loadFeedSources(path string) ([]models.Source, error) {
+	cfg, err := conf.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return feedSources(cfg.App.FeedURLs), nil
+}
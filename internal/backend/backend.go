@@ -0,0 +1,45 @@
+// Package backend содержит обращения к сервисам новостей и комментариев
+// через Kafka. Используется REST, GraphQL и gRPC транспортами.
+//
+// Запрос - строка пути с параметрами, как в исходном протоколе шлюза
+// ("/newslist/?n=10&page=1", "/newsdetail/42"), см. messages.go.
+// Ответ - следующее сообщение, прочитанное consumer'ом.
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+// Топики запросов к сервисам.
+const (
+	NewsTopic       = "news_input"
+	CommentsTopic   = "comments_input"
+	AddCommentTopic = "comment_input"
+)
+
+var (
+	// ErrSend - не удалось отправить запрос в Kafka.
+	ErrSend = errors.New("failed to write message in Kafka")
+	// ErrReceive - не удалось дождаться ответа сервиса.
+	ErrReceive = errors.New("failed to read message from Kafka")
+	// ErrBadResponse - ответ сервиса не удалось разобрать.
+	ErrBadResponse = errors.New("invalid response from backend")
+)
+
+// request отправляет сообщение в топик и возвращает ответ сервиса.
+// Ошибки Kafka оборачиваются вместе с причиной, так что истечение ctx
+// распознается через errors.Is(err, context.DeadlineExceeded).
+func request(ctx context.Context, c kfk.Cons, p kfk.Prod, topic, message string) ([]byte, error) {
+	if err := p.SendMessage(ctx, topic, []byte(message)); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSend, err)
+	}
+	reply, err := c.GetMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrReceive, err)
+	}
+	return reply.Value, nil
+}
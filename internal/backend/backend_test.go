@@ -0,0 +1,123 @@
+package backend
+
+import (
+	"apigateway/internal/models"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeService запоминает отправленное сообщение и отвечает reply.
+type fakeService struct {
+	topic, message string
+	reply          []byte
+	sendErr        error
+	wait           bool
+}
+
+func (f *fakeService) SendMessage(ctx context.Context, topic string, message []byte) error {
+	f.topic, f.message = topic, string(message)
+	return f.sendErr
+}
+
+func (f *fakeService) GetMessages(ctx context.Context) (kafka.Message, error) {
+	if f.wait {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	return kafka.Message{Value: f.reply}, nil
+}
+
+func TestRequest(t *testing.T) {
+	f := &fakeService{reply: []byte(`{"news_ id":42}`)}
+	raw, err := request(context.Background(), f, f, NewsTopic, newsDetailMessage(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.topic != NewsTopic || f.message != "/newsdetail/42" {
+		t.Errorf("sent %q to %s", f.message, f.topic)
+	}
+	if string(raw) != `{"news_ id":42}` {
+		t.Errorf("reply = %s", raw)
+	}
+
+	f = &fakeService{sendErr: errors.New("no brokers")}
+	if _, err := request(context.Background(), f, f, NewsTopic, "/newslist/"); !errors.Is(err, ErrSend) {
+		t.Errorf("send failure error = %v, want ErrSend", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	f = &fakeService{wait: true}
+	_, err = request(ctx, f, f, NewsTopic, "/newslist/")
+	if !errors.Is(err, ErrReceive) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("timeout error = %v, want ErrReceive and DeadlineExceeded", err)
+	}
+}
+
+func TestMessages(t *testing.T) {
+	parentID := 5
+	at := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	check := func(got, want string) {
+		t.Helper()
+		if got != want {
+			t.Errorf("message = %q, want %q", got, want)
+		}
+	}
+	check(newsListMessage(models.NewsListRequest{Page: 2, Limit: 10}), "/newslist/?n=10&page=2")
+	check(newsDetailMessage(42), "/newsdetail/42")
+	check(newsBatchMessage([]int{1, 2}), "/newsdetail/?id=1&id=2")
+	check(commentsMessage(42), "/comments/?newsID=42")
+	check(commentsMessage(1, 2), "/comments/?newsID=1&newsID=2")
+	check(sourceStatsMessage([]string{"habr"}), "/sources/?source=habr")
+	check(filterDateMessage(models.FilterDateRequest{StartDate: "a", EndDate: "b", Page: 1, Limit: 10}),
+		"newslist/filtered/?end_date=b&limit=10&page=1&start_date=a")
+	// Без news_id - как исходный /addcomment/?comment=.
+	check(addCommentMessage(models.AddCommentRequest{Content: "hi"}), "/add_comments/?comment=hi")
+	check(addCommentMessage(models.AddCommentRequest{NewsID: 3, ParentID: &parentID, Content: "hi"}),
+		"/add_comments/?comment=hi&news_id=3&parent_id=5")
+
+	msg := newsListMessage(models.NewsListRequest{Limit: 11, Sources: []string{"habr"}, After: &models.NewsCursorKey{PublishedAt: at, NewsID: 7}})
+	params := query(t, msg, "/newslist/")
+	if params.Has("page") || params.Get("n") != "11" || params.Get("source") != "habr" ||
+		params.Get("after_published_at") != "2026-10-19T12:00:00Z" || params.Get("after_news_id") != "7" {
+		t.Errorf("cursor message = %q", msg)
+	}
+
+	msg = filterContentMessage(models.FilterContentRequest{
+		NewsFilter: models.NewsFilter{Tags: models.FilterSet{Include: []string{"go"}, Exclude: []string{"java"}}, Match: models.MatchAll},
+		Page:       1,
+		Limit:      10,
+	})
+	params = query(t, msg, "newslist/filtered")
+	if tags := params["tags"]; len(tags) != 2 || tags[0] != "go" || tags[1] != "-java" || params.Get("match") != "all" {
+		t.Errorf("filter message = %q", msg)
+	}
+}
+
+func query(t *testing.T, msg, path string) url.Values {
+	t.Helper()
+	u, err := url.Parse(msg)
+	if err != nil || u.Path != path {
+		t.Fatalf("message %q: path %q, want %q (%v)", msg, u.Path, path, err)
+	}
+	return u.Query()
+}
+
+func TestDecodeNewsList(t *testing.T) {
+	list, err := DecodeNewsList([]byte(` [{"news_ id":1},{"news_ id":2}]`))
+	if err != nil || len(list.Items) != 2 || list.Total != 2 {
+		t.Errorf("array: %+v, %v", list, err)
+	}
+	list, err = DecodeNewsList([]byte(`{"items":[{"news_ id":1}],"total":30}`))
+	if err != nil || len(list.Items) != 1 || list.Total != 30 {
+		t.Errorf("object: %+v, %v", list, err)
+	}
+	if _, err := DecodeNewsList([]byte(`"oops"`)); !errors.Is(err, ErrBadResponse) {
+		t.Errorf("bad payload error = %v, want ErrBadResponse", err)
+	}
+}
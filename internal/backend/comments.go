@@ -0,0 +1,147 @@
+package backend
+
+import (
+	"apigateway/internal/censor"
+	"apigateway/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+// MaxCommentLength - максимальная длина комментария в символах.
+const MaxCommentLength = 4000
+
+// CommentError - ошибка публикации комментария с HTTP-статусом для клиента.
+// Upstream - сервис, из-за которого комментарий не опубликован.
+type CommentError struct {
+	Status   int
+	Message  string
+	Upstream string
+}
+
+func (e *CommentError) Error() string {
+	return e.Message
+}
+
+// CommentResult - результат публикации комментария.
+// Pending означает, что комментарий принят без проверки цензором.
+// Body - разобранный JSON-ответ сервиса комментариев.
+type CommentResult struct {
+	Status  int
+	Pending bool
+	Body    any
+}
+
+// Comments запрашивает комментарии к новости. Комментарии к другим
+// новостям (чужой ответ, прочитанный из общего топика) отбрасываются.
+func Comments(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, newsID int) ([]models.Comment, error) {
+	byNews, err := CommentsByNews(ctx, c, p, []int{newsID})
+	if err != nil {
+		return nil, err
+	}
+	return byNews[newsID], nil
+}
+
+// CommentsByNews запрашивает комментарии сразу к нескольким новостям одним
+// сообщением и раскладывает ответ по news_id. Комментарии к новостям
+// не из запроса отбрасываются; комментарий без news_id в ответе на запрос
+// по одной новости относится к ней.
+func CommentsByNews(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, newsIDs []int) (map[int][]models.Comment, error) {
+	raw, err := request(ctx, c, p, CommentsTopic, commentsMessage(newsIDs...))
+	if err != nil {
+		return nil, err
+	}
+	comments, err := DecodeComments(raw)
+	if err != nil {
+		return nil, err
+	}
+	byNews := make(map[int][]models.Comment, len(newsIDs))
+	for _, id := range newsIDs {
+		byNews[id] = nil
+	}
+	for _, cm := range comments {
+		id := cm.NewsID
+		if id == 0 && len(newsIDs) == 1 {
+			id = newsIDs[0]
+		}
+		if _, ok := byNews[id]; ok {
+			byNews[id] = append(byNews[id], cm)
+		}
+	}
+	return byNews, nil
+}
+
+// DecodeComments разбирает ответ сервиса комментариев в плоский список.
+func DecodeComments(raw []byte) ([]models.Comment, error) {
+	var comments []models.Comment
+	if err := json.Unmarshal(raw, &comments); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode comments: %v", ErrBadResponse, err)
+	}
+	return comments, nil
+}
+
+// ValidateComment проверяет новый комментарий. NewsID 0 - новость не указана,
+// как в исходном /addcomment/?comment=; ответ на комментарий требует NewsID.
+func ValidateComment(req models.AddCommentRequest) error {
+	content := strings.TrimSpace(req.Content)
+	switch {
+	case content == "":
+		return &CommentError{Status: http.StatusBadRequest, Message: "Invalid comment parameter"}
+	case utf8.RuneCountInString(content) > MaxCommentLength:
+		return &CommentError{Status: http.StatusBadRequest, Message: fmt.Sprintf("Comment is longer than %d characters", MaxCommentLength)}
+	case req.NewsID < 0, req.NewsID == 0 && req.ParentID != nil:
+		return &CommentError{Status: http.StatusBadRequest, Message: "Invalid news_id parameter"}
+	case req.ParentID != nil && *req.ParentID < 1:
+		return &CommentError{Status: http.StatusBadRequest, Message: "Invalid parent_id parameter"}
+	}
+	return nil
+}
+
+// SubmitComment проверяет комментарий, при необходимости пропускает его
+// через цензора и отправляет сервису комментариев.
+func SubmitComment(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, cens *censor.Client, req models.AddCommentRequest) (CommentResult, error) {
+	if err := ValidateComment(req); err != nil {
+		return CommentResult{}, err
+	}
+
+	if cens != nil {
+		verdict, err := cens.Check(ctx, req.Content)
+		switch {
+		case err == nil && !verdict.Allowed:
+			return CommentResult{}, &CommentError{Status: http.StatusUnprocessableEntity, Message: "Comment rejected: " + verdict.Reason}
+		case err != nil && cens.OnTimeout() == censor.PolicyReject:
+			return CommentResult{}, &CommentError{Status: http.StatusServiceUnavailable, Message: "Censor service unavailable", Upstream: "censor"}
+		case err != nil:
+			log.Printf("censor check failed, comment marked as pending: %v\n", err)
+			req.Pending = true
+		}
+	}
+
+	raw, err := request(ctx, c, p, AddCommentTopic, addCommentMessage(req))
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return CommentResult{}, &CommentError{Status: http.StatusGatewayTimeout, Message: "The comments service did not respond in time", Upstream: "comments"}
+	case errors.Is(err, ErrSend), errors.Is(err, ErrReceive):
+		return CommentResult{}, &CommentError{Status: http.StatusBadGateway, Message: "The comments service is unreachable", Upstream: "comments"}
+	case err != nil:
+		return CommentResult{}, &CommentError{Status: http.StatusInternalServerError, Message: "Failed to encode comment"}
+	}
+
+	var body any
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return CommentResult{}, &CommentError{Status: http.StatusBadGateway, Message: "Invalid response from comments service", Upstream: "comments"}
+	}
+
+	result := CommentResult{Status: http.StatusCreated, Pending: req.Pending, Body: body}
+	if req.Pending {
+		result.Status = http.StatusAccepted
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package backend
+
+import (
+	"apigateway/internal/models"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestValidateComment(t *testing.T) {
+	zero, one := 0, 1
+	for _, req := range []models.AddCommentRequest{
+		{Content: "hello"},
+		{NewsID: 1, Content: "hello"},
+		{NewsID: 1, ParentID: &one, Content: "hello"},
+		{NewsID: 1, Content: strings.Repeat("я", MaxCommentLength)},
+	} {
+		if err := ValidateComment(req); err != nil {
+			t.Errorf("ValidateComment(%+v) error = %v", req, err)
+		}
+	}
+
+	for _, req := range []models.AddCommentRequest{
+		{NewsID: 1, Content: "  \n"},
+		{NewsID: 1, Content: strings.Repeat("я", MaxCommentLength+1)},
+		{NewsID: -1, Content: "hello"},
+		{ParentID: &one, Content: "hello"},
+		{NewsID: 1, ParentID: &zero, Content: "hello"},
+	} {
+		var cerr *CommentError
+		if err := ValidateComment(req); !errors.As(err, &cerr) || cerr.Status != http.StatusBadRequest {
+			t.Errorf("ValidateComment(%+v) error = %v, want 400", req, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package backend
+
+import (
+	"apigateway/internal/models"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// newsListMessage - запрос страницы ленты: /newslist/?n=10&page=1.
+// В режиме курсора page не передается, граница задается after_*/before_*.
+func newsListMessage(req models.NewsListRequest) string {
+	params := url.Values{"n": {strconv.Itoa(req.Limit)}}
+	if req.Page > 0 {
+		params.Set("page", strconv.Itoa(req.Page))
+	}
+	if req.Filter != "" {
+		params.Set("filter", req.Filter)
+	}
+	for _, name := range req.Sources {
+		params.Add("source", name)
+	}
+	addCursorKey(params, "after", req.After)
+	addCursorKey(params, "before", req.Before)
+	return withQuery("/newslist/", params)
+}
+
+// filterContentMessage - выборка по фильтру: newslist/filtered?category=go&limit=10.
+// Исключаемые значения передаются с префиксом "-", как в запросе к шлюзу.
+func filterContentMessage(req models.FilterContentRequest) string {
+	params := url.Values{}
+	addFilterSet(params, "category", req.Categories)
+	addFilterSet(params, "author", req.Authors)
+	addFilterSet(params, "tags", req.Tags)
+	addFilterSet(params, "source", req.Sources)
+	if req.Match != "" {
+		params.Set("match", req.Match)
+	}
+	if req.Sort != "" {
+		params.Set("sort", req.Sort)
+	}
+	if req.DateFrom != "" {
+		params.Set("date_from", req.DateFrom)
+	}
+	if req.DateTo != "" {
+		params.Set("date_to", req.DateTo)
+	}
+	params.Set("page", strconv.Itoa(req.Page))
+	params.Set("limit", strconv.Itoa(req.Limit))
+	return withQuery("newslist/filtered", params)
+}
+
+// filterDateMessage - выборка за период: newslist/filtered/?start_date=...&end_date=....
+func filterDateMessage(req models.FilterDateRequest) string {
+	return withQuery("newslist/filtered/", url.Values{
+		"start_date": {req.StartDate},
+		"end_date":   {req.EndDate},
+		"page":       {strconv.Itoa(req.Page)},
+		"limit":      {strconv.Itoa(req.Limit)},
+	})
+}
+
+// newsDetailMessage - запрос одной новости: /newsdetail/42.
+func newsDetailMessage(newsID int) string {
+	return "/newsdetail/" + strconv.Itoa(newsID)
+}
+
+// newsBatchMessage - запрос нескольких новостей: /newsdetail/?id=1&id=2.
+func newsBatchMessage(ids []int) string {
+	return withQuery("/newsdetail/", intParams("id", ids))
+}
+
+// searchMessage - поисковый запрос: /search/?q=go&page=1&limit=10.
+func searchMessage(req models.SearchRequest) string {
+	return withQuery("/search/", url.Values{
+		"q":     {req.Query},
+		"page":  {strconv.Itoa(req.Page)},
+		"limit": {strconv.Itoa(req.Limit)},
+	})
+}
+
+// sourceStatsMessage - статистика источников: /sources/?source=a&source=b.
+func sourceStatsMessage(names []string) string {
+	return withQuery("/sources/", url.Values{"source": names})
+}
+
+// commentsMessage - комментарии к новостям: /comments/?newsID=42.
+// Для нескольких новостей параметр повторяется.
+func commentsMessage(newsIDs ...int) string {
+	return withQuery("/comments/", intParams("newsID", newsIDs))
+}
+
+// addCommentMessage - новый комментарий: /add_comments/?comment=....
+// news_id и parent_id передаются, если заданы.
+func addCommentMessage(req models.AddCommentRequest) string {
+	params := url.Values{"comment": {req.Content}}
+	if req.NewsID > 0 {
+		params.Set("news_id", strconv.Itoa(req.NewsID))
+	}
+	if req.ParentID != nil {
+		params.Set("parent_id", strconv.Itoa(*req.ParentID))
+	}
+	if req.Pending {
+		params.Set("pending", "true")
+	}
+	return withQuery("/add_comments/", params)
+}
+
+func withQuery(path string, params url.Values) string {
+	if len(params) == 0 {
+		return path
+	}
+	return path + "?" + params.Encode()
+}
+
+func intParams(name string, values []int) url.Values {
+	params := make(url.Values, 1)
+	for _, v := range values {
+		params.Add(name, strconv.Itoa(v))
+	}
+	return params
+}
+
+func addFilterSet(params url.Values, name string, set models.FilterSet) {
+	for _, v := range set.Include {
+		params.Add(name, v)
+	}
+	for _, v := range set.Exclude {
+		params.Add(name, "-"+v)
+	}
+}
+
+func addCursorKey(params url.Values, prefix string, key *models.NewsCursorKey) {
+	if key == nil {
+		return
+	}
+	params.Set(prefix+"_published_at", key.PublishedAt.UTC().Format(time.RFC3339Nano))
+	params.Set(prefix+"_news_id", strconv.Itoa(key.NewsID))
+}
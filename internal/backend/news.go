@@ -0,0 +1,125 @@
+package backend
+
+import (
+	"apigateway/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+// ListNews отправляет типизированный запрос списка новостей
+// (NewsListRequest, FilterContentRequest, FilterDateRequest) и разбирает ответ.
+func ListNews(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, req any) (models.NewsListResponse, error) {
+	var msg string
+	switch r := req.(type) {
+	case models.NewsListRequest:
+		msg = newsListMessage(r)
+	case models.FilterContentRequest:
+		msg = filterContentMessage(r)
+	case models.FilterDateRequest:
+		msg = filterDateMessage(r)
+	default:
+		return models.NewsListResponse{}, fmt.Errorf("unsupported news list request %T", req)
+	}
+	raw, err := request(ctx, c, p, NewsTopic, msg)
+	if err != nil {
+		return models.NewsListResponse{}, err
+	}
+	return DecodeNewsList(raw)
+}
+
+// DecodeNewsList разбирает ответ сервиса новостей.
+// Поддерживается как объект NewsListResponse, так и голый массив новостей.
+func DecodeNewsList(raw []byte) (models.NewsListResponse, error) {
+	var resp models.NewsListResponse
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &resp.Items); err != nil {
+			return models.NewsListResponse{}, fmt.Errorf("%w: failed to decode news list: %v", ErrBadResponse, err)
+		}
+		resp.Total = len(resp.Items)
+		return resp, nil
+	}
+	if err := json.Unmarshal(trimmed, &resp); err != nil {
+		return models.NewsListResponse{}, fmt.Errorf("%w: failed to decode news list: %v", ErrBadResponse, err)
+	}
+	return resp, nil
+}
+
+// NewsDetail запрашивает новость по идентификатору и возвращает ответ сервиса как есть.
+func NewsDetail(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, newsID int) ([]byte, error) {
+	return request(ctx, c, p, NewsTopic, newsDetailMessage(newsID))
+}
+
+// GetNews запрашивает новость по идентификатору и разбирает ответ.
+func GetNews(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, newsID int) (models.NewsFullDetailed, error) {
+	raw, err := NewsDetail(ctx, c, p, newsID)
+	if err != nil {
+		return models.NewsFullDetailed{}, err
+	}
+	var news models.NewsFullDetailed
+	if err := json.Unmarshal(raw, &news); err != nil {
+		return models.NewsFullDetailed{}, fmt.Errorf("%w: failed to decode news: %v", ErrBadResponse, err)
+	}
+	if news.NewsID != 0 && news.NewsID != newsID {
+		return models.NewsFullDetailed{}, fmt.Errorf("%w: got news %d, requested %d", ErrBadResponse, news.NewsID, newsID)
+	}
+	return news, nil
+}
+
+// NewsByIDs запрашивает новости по списку id одним сообщением. Новости,
+// которых нет в ответе, не найдены; новости с id не из запроса отбрасываются.
+func NewsByIDs(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, ids []int) (map[int]models.NewsFullDetailed, error) {
+	raw, err := request(ctx, c, p, NewsTopic, newsBatchMessage(ids))
+	if err != nil {
+		return nil, err
+	}
+	list, err := DecodeNewsList(raw)
+	if err != nil {
+		return nil, err
+	}
+	requested := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		requested[id] = true
+	}
+	news := make(map[int]models.NewsFullDetailed, len(ids))
+	for _, n := range list.Items {
+		if requested[n.NewsID] {
+			news[n.NewsID] = n
+		}
+	}
+	return news, nil
+}
+
+// Search отправляет поисковый запрос сервису новостей.
+func Search(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, req models.SearchRequest) (models.SearchResponse, error) {
+	raw, err := request(ctx, c, p, NewsTopic, searchMessage(req))
+	if err != nil {
+		return models.SearchResponse{}, err
+	}
+	var resp models.SearchResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return models.SearchResponse{}, fmt.Errorf("%w: failed to decode search response: %v", ErrBadResponse, err)
+	}
+	return resp, nil
+}
+
+// SourceStats запрашивает у сервиса новостей статистику по источникам.
+func SourceStats(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, names []string) (map[string]models.SourceStats, error) {
+	raw, err := request(ctx, c, p, NewsTopic, sourceStatsMessage(names))
+	if err != nil {
+		return nil, err
+	}
+	var resp models.SourceStatsResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode source stats: %v", ErrBadResponse, err)
+	}
+	stats := make(map[string]models.SourceStats, len(resp.Items))
+	for _, st := range resp.Items {
+		stats[st.Name] = st
+	}
+	return stats, nil
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTTLExpires(t *testing.T) {
+	c := New[string](10*time.Millisecond, 4)
+	c.Set("a", "va")
+	if v, ok := c.Get("a"); !ok || v != "va" {
+		t.Fatalf("Get(a) = %q, %v; want va", v, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) hit for a missing key")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) hit after expiry")
+	}
+}
+
+func TestTTLEvictsOldest(t *testing.T) {
+	c := New[int](time.Minute, 2)
+	c.Set("a", 1)
+	time.Sleep(time.Millisecond)
+	c.Set("b", 2)
+	time.Sleep(time.Millisecond)
+	// Перезапись существующего ключа не вытесняет другие записи.
+	c.Set("b", 3)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("overwrite evicted a")
+	}
+
+	c.Set("c", 4)
+	if _, ok := c.Get("a"); ok {
+		t.Error("oldest entry a was not evicted")
+	}
+	if v, ok := c.Get("b"); !ok || v != 3 {
+		t.Errorf("Get(b) = %d, %v; want 3", v, ok)
+	}
+	if v, ok := c.Get("c"); !ok || v != 4 {
+		t.Errorf("Get(c) = %d, %v; want 4", v, ok)
+	}
+}
@@ -0,0 +1,116 @@
+package censor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Policy - поведение при медленном или недоступном сервисе цензуры.
+type Policy string
+
+const (
+	// PolicyPending принимает комментарий и помечает его как ожидающий модерации.
+	PolicyPending Policy = "pending"
+	// PolicyReject отклоняет комментарий, пока цензор не ответит.
+	PolicyReject Policy = "reject"
+)
+
+const defaultTimeout = 500 * time.Millisecond
+
+// ErrUnavailable возвращается, если цензор не ответил вовремя или вернул ошибку.
+var ErrUnavailable = errors.New("censor service unavailable")
+
+// Verdict - решение сервиса цензуры.
+type Verdict struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+type checkRequest struct {
+	Content string `json:"content"`
+}
+
+// Client - HTTP клиент сервиса цензуры.
+type Client struct {
+	url       string
+	client    *http.Client
+	timeout   time.Duration
+	onTimeout Policy
+}
+
+// New создает клиент сервиса цензуры.
+func New(baseURL, path string, timeout time.Duration, onTimeout Policy) *Client {
+	if onTimeout != PolicyReject {
+		onTimeout = PolicyPending
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &Client{
+		url:       strings.TrimRight(baseURL, "/") + path,
+		client:    &http.Client{},
+		timeout:   timeout,
+		onTimeout: onTimeout,
+	}
+}
+
+// OnTimeout возвращает политику обработки недоступности цензора.
+func (c *Client) OnTimeout() Policy {
+	return c.onTimeout
+}
+
+// Check проверяет текст комментария.
+// Ответы 400 и 422 считаются отказом, причина берется из тела ответа.
+func (c *Client) Check(ctx context.Context, text string) (Verdict, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	body, err := json.Marshal(checkRequest{Content: text})
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to encode censor request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, fmt.Errorf("failed to create censor request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return Verdict{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	var verdict Verdict
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity:
+		_ = json.Unmarshal(raw, &verdict)
+		verdict.Allowed = false
+		if verdict.Reason == "" {
+			verdict.Reason = "comment contains forbidden content"
+		}
+		return verdict, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		if len(bytes.TrimSpace(raw)) == 0 {
+			return Verdict{Allowed: true}, nil
+		}
+		if err := json.Unmarshal(raw, &verdict); err != nil {
+			return Verdict{}, fmt.Errorf("%w: invalid response: %v", ErrUnavailable, err)
+		}
+		return verdict, nil
+	default:
+		return Verdict{}, fmt.Errorf("%w: unexpected status %d", ErrUnavailable, resp.StatusCode)
+	}
+}
@@ -0,0 +1,148 @@
+// Package codec выбирает формат ответа REST API по заголовку Accept:
+// JSON (по умолчанию), MessagePack или protobuf.
+package codec
+
+import (
+	"apigateway/internal/problem"
+	"bytes"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	httputils "github.com/Fau1con/renderresponse"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Типы содержимого ответов.
+const (
+	JSON     = "application/json"
+	MsgPack  = "application/msgpack"
+	Protobuf = "application/x-protobuf"
+)
+
+// aliases - принятые синонимы поддерживаемых типов.
+var aliases = map[string]string{
+	JSON:                      JSON,
+	MsgPack:                   MsgPack,
+	"application/x-msgpack":   MsgPack,
+	"application/vnd.msgpack": MsgPack,
+	Protobuf:                  Protobuf,
+	"application/protobuf":    Protobuf,
+}
+
+// envelope - обертка успешного ответа, как у RenderJSON.
+type envelope struct {
+	Status string `json:"status"`
+	Data   any    `json:"data"`
+}
+
+// Render отдает v со статусом status в формате, выбранном по Accept.
+// JSON и MessagePack оборачиваются в {status, data}; protobuf отдается
+// сообщением из api/news/v1 без обертки. Если клиент принимает только
+// форматы, в которых v не представим, ответ - 406.
+func Render(w http.ResponseWriter, r *http.Request, v any, status int) {
+	w.Header().Add("Vary", "Accept")
+	for _, format := range Negotiate(r.Header.Get("Accept")) {
+		switch format {
+		case JSON:
+			httputils.RenderJSON(w, v, status)
+			return
+		case MsgPack:
+			body, err := encodeMsgPack(envelope{Status: "success", Data: v})
+			if err != nil {
+				problem.Render(w, r, err)
+				return
+			}
+			write(w, MsgPack, status, body)
+			return
+		case Protobuf:
+			msg, ok, err := toProto(v)
+			if !ok {
+				continue
+			}
+			var body []byte
+			if err == nil {
+				body, err = proto.Marshal(msg)
+			}
+			if err != nil {
+				problem.Render(w, r, err)
+				return
+			}
+			write(w, Protobuf, status, body)
+			return
+		}
+	}
+	problem.Respond(w, r, problem.NotAcceptable, "Supported formats: "+strings.Join([]string{JSON, MsgPack, Protobuf}, ", "))
+}
+
+// Negotiate возвращает поддерживаемые форматы в порядке предпочтения клиента.
+// Пустой Accept, */* и application/* означают JSON.
+func Negotiate(accept string) []string {
+	if strings.TrimSpace(accept) == "" {
+		return []string{JSON}
+	}
+
+	type mediaRange struct {
+		format string
+		q      float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		media := strings.ToLower(strings.TrimSpace(fields[0]))
+		q := 1.0
+		for _, param := range fields[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(name, "q") {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		format, ok := aliases[media]
+		if !ok && (media == "*/*" || media == "application/*") {
+			format, ok = JSON, true
+		}
+		if ok {
+			ranges = append(ranges, mediaRange{format: format, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	formats := make([]string, 0, len(ranges))
+	seen := make(map[string]bool, len(ranges))
+	for _, mr := range ranges {
+		if !seen[mr.format] {
+			seen[mr.format] = true
+			formats = append(formats, mr.format)
+		}
+	}
+	return formats
+}
+
+// encodeMsgPack кодирует v в MessagePack с именами полей из json-тегов.
+func encodeMsgPack(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func write(w http.ResponseWriter, contentType string, status int, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("failed to write %s response: %v\n", contentType, err)
+	}
+}
@@ -0,0 +1,99 @@
+package codec
+
+import (
+	newsv1 "apigateway/api/news/v1"
+	"apigateway/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// render отдает v с заголовком Accept и возвращает записанный ответ.
+func render(accept string, v any) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	Render(w, r, v, http.StatusOK)
+	return w
+}
+
+func TestNegotiate(t *testing.T) {
+	if got := Negotiate(""); !slices.Equal(got, []string{JSON}) {
+		t.Errorf("Negotiate(\"\") = %v, want JSON", got)
+	}
+	if got := Negotiate("application/x-protobuf, */*;q=0.1"); !slices.Equal(got, []string{Protobuf, JSON}) {
+		t.Errorf("Negotiate(protobuf, */*) = %v", got)
+	}
+	if got := Negotiate("application/x-protobuf;q=0.2, application/json;q=0.9"); !slices.Equal(got, []string{JSON, Protobuf}) {
+		t.Errorf("Negotiate by q = %v", got)
+	}
+	if got := Negotiate("APPLICATION/X-MSGPACK"); !slices.Equal(got, []string{MsgPack}) {
+		t.Errorf("Negotiate(msgpack alias) = %v", got)
+	}
+	if got := Negotiate("application/json;q=0, text/html"); len(got) != 0 {
+		t.Errorf("Negotiate(nothing acceptable) = %v, want none", got)
+	}
+}
+
+func TestRenderNotAcceptable(t *testing.T) {
+	w := render(Protobuf, map[string]int{"a": 1})
+	if w.Code != http.StatusNotAcceptable {
+		t.Errorf("status = %d, want 406", w.Code)
+	}
+	if !slices.Contains(w.Header().Values("Vary"), "Accept") {
+		t.Errorf("Vary = %v, want Accept", w.Header().Values("Vary"))
+	}
+}
+
+func TestRenderNewsDetail(t *testing.T) {
+	title := "title"
+	detail := models.FinalResponse{
+		News:     models.NewsFullDetailed{NewsID: 7, Title: "title"},
+		Comments: []*models.CommentNode{},
+	}
+	sparse := models.FinalResponse{News: models.NewsSparse{NewsID: 7, Title: &title}}
+
+	for _, v := range []models.FinalResponse{detail, sparse} {
+		var body struct {
+			Data struct {
+				News struct {
+					NewsID int    `json:"news_ id"`
+					Title  string `json:"title"`
+				} `json:"news"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(render("", v).Body.Bytes(), &body); err != nil {
+			t.Fatalf("json: %v", err)
+		}
+		if body.Data.News.NewsID != 7 || body.Data.News.Title != "title" {
+			t.Errorf("json news = %+v, want object with id 7", body.Data.News)
+		}
+
+		var packed struct {
+			Data struct {
+				News map[string]any `msgpack:"news"`
+			} `msgpack:"data"`
+		}
+		if err := msgpack.Unmarshal(render(MsgPack, v).Body.Bytes(), &packed); err != nil {
+			t.Fatalf("msgpack: %v", err)
+		}
+		if packed.Data.News["title"] != "title" {
+			t.Errorf("msgpack news = %v, want map with title", packed.Data.News)
+		}
+
+		var msg newsv1.NewsDetail
+		if err := proto.Unmarshal(render(Protobuf, v).Body.Bytes(), &msg); err != nil {
+			t.Fatalf("protobuf: %v", err)
+		}
+		if msg.GetNews().GetId() != 7 || msg.GetNews().GetTitle() != "title" {
+			t.Errorf("protobuf news = %v, want id 7", msg.GetNews())
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package codec
+
+import (
+	newsv1 "apigateway/api/news/v1"
+	"apigateway/internal/models"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NewsToProto переводит новость в сообщение protobuf.
+func NewsToProto(n models.NewsFullDetailed) *newsv1.News {
+	news := &newsv1.News{
+		Id:          int64(n.NewsID),
+		Title:       n.Title,
+		Description: n.Description,
+		Content:     n.Content,
+		Author:      n.Author,
+		PublishedAt: timestamppb.New(n.PublishedAt),
+		Source:      n.Source,
+		Link:        n.Link,
+		Tags:        n.Tag,
+	}
+	if n.CommentCount != nil {
+		news.CommentCount = proto.Int32(int32(*n.CommentCount))
+	}
+	return news
+}
+
+// CommentToProto переводит комментарий в сообщение protobuf.
+func CommentToProto(c models.Comment) *newsv1.Comment {
+	comment := &newsv1.Comment{
+		Id:         int64(c.CommentID),
+		NewsId:     int64(c.NewsID),
+		Message:    c.Message,
+		CreatedAt:  timestamppb.New(c.CreatedAt),
+		Moderation: string(c.State()),
+	}
+	if c.ParentID != nil {
+		parentID := int64(*c.ParentID)
+		comment.ParentId = &parentID
+	}
+	return comment
+}
+
+// toProto подбирает сообщение из api/news/v1 для модели ответа.
+// ok == false, если у модели нет protobuf-представления.
+func toProto(v any) (msg proto.Message, ok bool, err error) {
+	switch v := v.(type) {
+	case proto.Message:
+		return v, true, nil
+	case models.NewsFullDetailed:
+		return NewsToProto(v), true, nil
+	case models.ListEnvelope[models.NewsFullDetailed]:
+		page := &newsv1.NewsPage{Items: make([]*newsv1.News, len(v.Items)), Page: pageInfo(v)}
+		for i, n := range v.Items {
+			page.Items[i] = NewsToProto(n)
+		}
+		return page, true, nil
+	case models.ListEnvelope[models.SearchHit]:
+		page := &newsv1.SearchPage{Items: make([]*newsv1.SearchHit, len(v.Items)), Page: pageInfo(v)}
+		for i, hit := range v.Items {
+			page.Items[i] = &newsv1.SearchHit{
+				News:             NewsToProto(hit.News),
+				Score:            hit.Score,
+				HighlightTitle:   hit.Highlight.Title,
+				HighlightSnippet: hit.Highlight.Snippet,
+			}
+		}
+		return page, true, nil
+	case models.NewsSparse:
+		return sparseToProto(v), true, nil
+	case models.ListEnvelope[models.NewsSparse]:
+		page := &newsv1.NewsPage{Items: make([]*newsv1.News, len(v.Items)), Page: pageInfo(v)}
+		for i, n := range v.Items {
+			page.Items[i] = sparseToProto(n)
+		}
+		return page, true, nil
+	case models.ListEnvelope[models.SearchHitSparse]:
+		page := &newsv1.SearchPage{Items: make([]*newsv1.SearchHit, len(v.Items)), Page: pageInfo(v)}
+		for i, hit := range v.Items {
+			item := &newsv1.SearchHit{News: sparseToProto(hit.News)}
+			if hit.Score != nil {
+				item.Score = *hit.Score
+			}
+			if hit.Highlight != nil {
+				item.HighlightTitle = hit.Highlight.Title
+				item.HighlightSnippet = hit.Highlight.Snippet
+			}
+			page.Items[i] = item
+		}
+		return page, true, nil
+	case models.NewsBatch:
+		batch := &newsv1.NewsBatch{Items: make(map[int64]*newsv1.NewsBatchItem, len(v.Items))}
+		for id, item := range v.Items {
+			out := &newsv1.NewsBatchItem{}
+			switch n := item.News.(type) {
+			case models.NewsFullDetailed:
+				out.News = NewsToProto(n)
+			case models.NewsSparse:
+				out.News = sparseToProto(n)
+			}
+			if item.Error != nil {
+				out.Error = &newsv1.BatchError{Status: int32(item.Error.Status), Title: item.Error.Title, Detail: item.Error.Detail}
+			}
+			batch.Items[int64(id)] = out
+		}
+		return batch, true, nil
+	case models.CommentThread:
+		thread := &newsv1.CommentThread{
+			Items:  commentNodes(v.Items),
+			Page:   pageInfo(v.ListEnvelope),
+			NewsId: int64(v.NewsID),
+			View:   v.View,
+		}
+		if v.ParentID != nil {
+			parentID := int64(*v.ParentID)
+			thread.ParentId = &parentID
+		}
+		return thread, true, nil
+	case models.FinalResponse:
+		detail := &newsv1.NewsDetail{Comments: commentNodes(v.Comments)}
+		switch news := v.News.(type) {
+		case models.NewsFullDetailed:
+			detail.News = NewsToProto(news)
+		case models.NewsSparse:
+			detail.News = sparseToProto(news)
+		}
+		return detail, true, nil
+	case models.Source:
+		return sourceToProto(v), true, nil
+	case []models.Source:
+		list := &newsv1.SourceList{Items: make([]*newsv1.Source, len(v))}
+		for i, s := range v {
+			list.Items[i] = sourceToProto(s)
+		}
+		return list, true, nil
+	}
+	return nil, false, nil
+}
+
+// sparseToProto переводит новость с выборкой полей; невыбранные поля не заполняются.
+func sparseToProto(n models.NewsSparse) *newsv1.News {
+	news := &newsv1.News{
+		Id:          int64(n.NewsID),
+		Title:       deref(n.Title),
+		Description: deref(n.Description),
+		Content:     deref(n.Content),
+		Author:      deref(n.Author),
+		Source:      deref(n.Source),
+		Link:        deref(n.Link),
+		Tags:        n.Tag,
+	}
+	if n.PublishedAt != nil {
+		news.PublishedAt = timestamppb.New(*n.PublishedAt)
+	}
+	if n.CommentCount != nil {
+		news.CommentCount = proto.Int32(int32(*n.CommentCount))
+	}
+	return news
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func pageInfo[T any](env models.ListEnvelope[T]) *newsv1.PageInfo {
+	return &newsv1.PageInfo{
+		Page:       int32(env.Page),
+		Limit:      int32(env.Limit),
+		Total:      int32(env.Total),
+		TotalPages: int32(env.TotalPages),
+		HasNext:    env.HasNext,
+		Next:       env.Next,
+		Prev:       env.Prev,
+	}
+}
+
+func commentNodes(nodes []*models.CommentNode) []*newsv1.CommentNode {
+	out := make([]*newsv1.CommentNode, len(nodes))
+	for i, n := range nodes {
+		out[i] = &newsv1.CommentNode{
+			Comment: CommentToProto(n.Comment),
+			Depth:   int32(n.Depth),
+			Replies: commentNodes(n.Replies),
+		}
+	}
+	return out
+}
+
+func sourceToProto(s models.Source) *newsv1.Source {
+	src := &newsv1.Source{Name: s.Name, Url: s.URL, ArticleCount: int32(s.ArticleCount)}
+	if s.LastFetch != nil {
+		src.LastFetch = timestamppb.New(*s.LastFetch)
+	}
+	return src
+}
@@ -0,0 +1,198 @@
+package feed
+
+import (
+	"apigateway/internal/models"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Channel - описание ленты GoNews.
+type Channel struct {
+	Title       string
+	Description string
+	Link        string
+	SelfLink    string
+}
+
+// Updated возвращает время публикации самой свежей новости.
+func Updated(items []models.NewsFullDetailed) time.Time {
+	var updated time.Time
+	for _, n := range items {
+		if n.PublishedAt.After(updated) {
+			updated = n.PublishedAt
+		}
+	}
+	return updated
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Creator     string   `xml:"dc:creator,omitempty"`
+	Categories  []string `xml:"category"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Source      string   `xml:"dc:source,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS формирует документ RSS 2.0.
+func RSS(ch Channel, items []models.NewsFullDetailed) ([]byte, error) {
+	doc := rss{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       ch.Title,
+			Link:        ch.Link,
+			Description: ch.Description,
+			AtomLink:    atomLink{Href: ch.SelfLink, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if updated := Updated(items); !updated.IsZero() {
+		doc.Channel.LastBuildDate = updated.Format(time.RFC1123Z)
+	}
+	for _, n := range items {
+		item := rssItem{
+			Title:       n.Title,
+			Link:        n.Link,
+			Description: n.Description,
+			Creator:     n.Author,
+			Categories:  n.Tag,
+			GUID:        rssGUID{Value: guid(ch, n)},
+			Source:      n.Source,
+		}
+		if !n.PublishedAt.IsZero() {
+			item.PubDate = n.PublishedAt.Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return encode(doc)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Links      []atomLink     `xml:"link"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// Atom формирует документ Atom 1.0.
+func Atom(ch Channel, items []models.NewsFullDetailed) ([]byte, error) {
+	updated := Updated(items)
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+	doc := atomFeed{
+		Title:   ch.Title,
+		ID:      ch.SelfLink,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: ch.Title},
+		Links: []atomLink{
+			{Href: ch.SelfLink, Rel: "self", Type: "application/atom+xml"},
+			{Href: ch.Link, Rel: "alternate"},
+		},
+	}
+	for _, n := range items {
+		published := n.PublishedAt.UTC().Format(time.RFC3339)
+		if n.PublishedAt.IsZero() {
+			published = doc.Updated
+		}
+		entry := atomEntry{
+			Title:     n.Title,
+			ID:        guid(ch, n),
+			Updated:   published,
+			Published: published,
+			Summary:   n.Description,
+		}
+		if n.Link != "" {
+			entry.Links = []atomLink{{Href: n.Link, Rel: "alternate"}}
+		}
+		if n.Author != "" {
+			entry.Author = &atomAuthor{Name: n.Author}
+		}
+		for _, tag := range n.Tag {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		if n.Content != "" {
+			entry.Content = &atomContent{Type: "html", Value: n.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return encode(doc)
+}
+
+// guid - постоянный идентификатор новости в ленте.
+func guid(ch Channel, n models.NewsFullDetailed) string {
+	return ch.Link + "/newsdetail?id=" + strconv.Itoa(n.NewsID)
+}
+
+func encode(doc any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,203 @@
+// Package fields реализует выборку полей новостей в ответах API:
+// параметр fields=title,link, сокращенное представление view=short
+// и дополнительные данные include=comment_count.
+package fields
+
+import (
+	"apigateway/internal/models"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Поля новости, доступные для выборки. news_ id возвращается всегда.
+var newsFields = []string{"title", "description", "content", "author", "published_at", "source", "link", "tag"}
+
+// shortFields - поля представления view=short (models.NewsShortDetailed).
+var shortFields = []string{"title", "description"}
+
+// IncludeCommentCount - число видимых комментариев у каждой новости списка.
+const IncludeCommentCount = "comment_count"
+
+// Resource - ресурс API, допустимые для него поля и значения include.
+type Resource struct {
+	Name     string
+	Fields   []string
+	Includes []string
+}
+
+// Ресурсы с выборкой полей.
+var (
+	News     = Resource{Name: "news", Fields: newsFields}
+	NewsList = Resource{Name: "news list", Fields: newsFields, Includes: []string{IncludeCommentCount}}
+	Search   = Resource{Name: "search", Fields: append(slices.Clone(newsFields), "score", "highlight")}
+)
+
+// Selection - выбранные поля; нулевое значение означает все поля.
+type Selection struct {
+	set     map[string]bool
+	include map[string]bool
+}
+
+// Parse читает параметры fields, view и include и проверяет их по ресурсу.
+// fields и include принимают список через запятую или повторением параметра.
+func Parse(query url.Values, res Resource) (Selection, error) {
+	view := query.Get("view")
+	switch view {
+	case "", "full", "short":
+	default:
+		return Selection{}, fmt.Errorf("invalid view %q: want short or full", view)
+	}
+
+	var sel Selection
+	for _, name := range splitList(query["include"]) {
+		if !slices.Contains(res.Includes, name) {
+			if len(res.Includes) == 0 {
+				return Selection{}, fmt.Errorf("include is not supported for %s", res.Name)
+			}
+			return Selection{}, fmt.Errorf("unknown include %q for %s, allowed: %s", name, res.Name, strings.Join(res.Includes, ", "))
+		}
+		if sel.include == nil {
+			sel.include = make(map[string]bool, len(res.Includes))
+		}
+		sel.include[name] = true
+	}
+
+	names := splitList(query["fields"])
+	if len(names) > 0 && view == "short" {
+		return Selection{}, errors.New("fields can't be combined with view=short")
+	}
+	if view == "short" {
+		names = shortFields
+	}
+	if len(names) == 0 {
+		return sel, nil
+	}
+
+	sel.set = make(map[string]bool, len(names))
+	for _, name := range names {
+		if !slices.Contains(res.Fields, name) {
+			return Selection{}, fmt.Errorf("unknown field %q for %s, allowed: %s", name, res.Name, strings.Join(res.Fields, ", "))
+		}
+		sel.set[name] = true
+	}
+	return sel, nil
+}
+
+func splitList(values []string) []string {
+	var names []string
+	for _, raw := range values {
+		for _, name := range strings.Split(raw, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// All сообщает, что выборка не задана.
+func (s Selection) All() bool {
+	return s.set == nil
+}
+
+// Has сообщает, выбрано ли поле.
+func (s Selection) Has(name string) bool {
+	return s.set == nil || s.set[name]
+}
+
+// Includes сообщает, запрошено ли дополнение include.
+func (s Selection) Includes(name string) bool {
+	return s.include[name]
+}
+
+// News возвращает новость с выбранными полями. comment_count переносится,
+// если он заполнен (include=comment_count).
+func (s Selection) News(n models.NewsFullDetailed) models.NewsSparse {
+	sparse := models.NewsSparse{NewsID: n.NewsID, CommentCount: n.CommentCount}
+	if s.Has("title") {
+		sparse.Title = &n.Title
+	}
+	if s.Has("description") {
+		sparse.Description = &n.Description
+	}
+	if s.Has("content") {
+		sparse.Content = &n.Content
+	}
+	if s.Has("author") {
+		sparse.Author = &n.Author
+	}
+	if s.Has("published_at") {
+		sparse.PublishedAt = &n.PublishedAt
+	}
+	if s.Has("source") {
+		sparse.Source = &n.Source
+	}
+	if s.Has("link") {
+		sparse.Link = &n.Link
+	}
+	if s.Has("tag") {
+		sparse.Tag = n.Tag
+	}
+	return sparse
+}
+
+// Item применяет выборку к одной новости. Без выборки новость не меняется.
+func (s Selection) Item(n models.NewsFullDetailed) any {
+	if s.All() {
+		return n
+	}
+	return s.News(n)
+}
+
+// NewsList применяет выборку к странице ленты.
+func (s Selection) NewsList(env models.ListEnvelope[models.NewsFullDetailed]) any {
+	if s.All() {
+		return env
+	}
+	return mapEnvelope(env, s.News)
+}
+
+// SearchList применяет выборку к странице результатов поиска.
+func (s Selection) SearchList(env models.ListEnvelope[models.SearchHit]) any {
+	if s.All() {
+		return env
+	}
+	return mapEnvelope(env, func(hit models.SearchHit) models.SearchHitSparse {
+		sparse := models.SearchHitSparse{News: s.News(hit.News)}
+		if s.Has("score") {
+			sparse.Score = &hit.Score
+		}
+		if s.Has("highlight") {
+			sparse.Highlight = &hit.Highlight
+		}
+		return sparse
+	})
+}
+
+// Detail применяет выборку к новости ответа /newsdetail; комментарии не меняются.
+func (s Selection) Detail(resp models.FinalResponse) models.FinalResponse {
+	if news, ok := resp.News.(models.NewsFullDetailed); ok {
+		resp.News = s.Item(news)
+	}
+	return resp
+}
+
+func mapEnvelope[T, U any](env models.ListEnvelope[T], fn func(T) U) models.ListEnvelope[U] {
+	items := make([]U, len(env.Items))
+	for i, item := range env.Items {
+		items[i] = fn(item)
+	}
+	return models.ListEnvelope[U]{
+		Items:      items,
+		Page:       env.Page,
+		Limit:      env.Limit,
+		Total:      env.Total,
+		TotalPages: env.TotalPages,
+		HasNext:    env.HasNext,
+		Next:       env.Next,
+		Prev:       env.Prev,
+	}
+}
@@ -0,0 +1,97 @@
+package fields
+
+import (
+	"apigateway/internal/models"
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func parse(t *testing.T, raw string, res Resource) Selection {
+	t.Helper()
+	query, _ := url.ParseQuery(raw)
+	sel, err := Parse(query, res)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", raw, err)
+	}
+	return sel
+}
+
+func TestParse(t *testing.T) {
+	if sel := parse(t, "view=full", News); !sel.All() {
+		t.Error("view=full selects a subset of fields")
+	}
+
+	sel := parse(t, "fields=title,%20link&fields=,tag", News)
+	for _, name := range []string{"title", "link", "tag"} {
+		if !sel.Has(name) {
+			t.Errorf("field %s is not selected", name)
+		}
+	}
+	if sel.Has("content") {
+		t.Error("content is selected")
+	}
+
+	sel = parse(t, "view=short", News)
+	if !sel.Has("title") || !sel.Has("description") || sel.Has("link") {
+		t.Errorf("view=short selects %v", sel.set)
+	}
+
+	if !parse(t, "include=comment_count", NewsList).Includes(IncludeCommentCount) {
+		t.Error("include=comment_count is not set")
+	}
+	if !parse(t, "fields=score,highlight", Search).Has("score") {
+		t.Error("search field score is not selected")
+	}
+
+	for raw, res := range map[string]Resource{
+		"view=tiny":               News,
+		"fields=title,secret":     News,
+		"fields=score":            News,
+		"view=short&fields=title": News,
+		"include=likes":           NewsList,
+		"include=comment_count":   News,
+	} {
+		query, _ := url.ParseQuery(raw)
+		if _, err := Parse(query, res); err == nil {
+			t.Errorf("Parse(%q) for %s succeeded, want error", raw, res.Name)
+		}
+	}
+}
+
+func TestSelectionNews(t *testing.T) {
+	count := 2
+	n := models.NewsFullDetailed{NewsID: 7, Title: "title", Content: "content", Tag: []string{"go"}, CommentCount: &count}
+
+	raw, err := json.Marshal(parse(t, "fields=title,tag", News).Item(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"news_ id":7,"title":"title","tag":["go"],"comment_count":2}`; string(raw) != want {
+		t.Errorf("Item() = %s, want %s", raw, want)
+	}
+
+	if _, ok := (Selection{}).Item(n).(models.NewsFullDetailed); !ok {
+		t.Error("empty selection changes the news type")
+	}
+}
+
+func TestSelectionDetail(t *testing.T) {
+	resp := models.FinalResponse{
+		News:     models.NewsFullDetailed{NewsID: 7, Title: "title", Content: "content"},
+		Comments: []*models.CommentNode{{}},
+	}
+
+	got := parse(t, "view=short", News).Detail(resp)
+	news, ok := got.News.(models.NewsSparse)
+	if !ok || news.Title == nil || news.Content != nil {
+		t.Errorf("Detail().News = %#v, want short news", got.News)
+	}
+	if len(got.Comments) != 1 {
+		t.Errorf("Detail() changed comments: %v", got.Comments)
+	}
+
+	if full, ok := (Selection{}).Detail(resp).News.(models.NewsFullDetailed); !ok || full.Content != "content" {
+		t.Errorf("empty selection changed the news: %#v", full)
+	}
+}
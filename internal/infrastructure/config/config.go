@@ -11,13 +11,16 @@ import (
 
 // AppConfig - конфигурация приложения.
 type AppConfig struct {
-	Name               string    `yaml:"name"`
-	ReadTimeout        int       `yaml:"read_timeout"`
-	WriteTimeout       int       `yaml:"write_timeout"`
-	ConnectTimeout     int       `yaml:"connect_timeout"`
-	DefaultNewsLimit   int       `yaml:"default_news_limit"`
-	ProcessingInterval int       `yaml:"processingInterval"`
-	FeedURLs           []FeedURL `yaml:"feed_urls"`
+	Name                  string    `yaml:"name"`
+	ReadTimeout           int       `yaml:"read_timeout"`
+	WriteTimeout          int       `yaml:"write_timeout"`
+	ConnectTimeout        int       `yaml:"connect_timeout"`
+	DefaultNewsLimit      int       `yaml:"default_news_limit"`
+	ProcessingInterval    int       `yaml:"processingInterval"`
+	FeedURLs              []FeedURL `yaml:"feed_urls"`
+	CursorSecret          string    `yaml:"cursor_secret"`
+	SourcesReloadInterval int       `yaml:"sources_reload_interval"`
+	FeedCacheTTL          int       `yaml:"feed_cache_ttl"`
 }
 
 type FeedURL struct {
@@ -31,6 +34,13 @@ type HTTPConfig struct {
 	Port int    `yaml:"port"`
 }
 
+// GRPCConfig - конфигурация gRPC сервера.
+type GRPCConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Host    string `yaml:"host"`
+	Port    int    `yaml:"port"`
+}
+
 // LoggingConfig - конфигурация логирования.
 type LoggingConfig struct {
 	Level  string `yaml:"level"`
@@ -43,6 +53,102 @@ type Route struct {
 	BaseURL string `yaml:"base_url"`
 }
 
+// CensorConfig - настройки предварительной проверки комментариев.
+type CensorConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Route     string `yaml:"route"`
+	Path      string `yaml:"path"`
+	TimeoutMs int    `yaml:"timeout_ms"`
+	OnTimeout string `yaml:"on_timeout"`
+}
+
+// SearchConfig - настройки полнотекстового поиска.
+type SearchConfig struct {
+	BackendTimeoutMs int  `yaml:"backend_timeout_ms"`
+	LocalIndex       bool `yaml:"local_index"`
+	MaxDocuments     int  `yaml:"max_documents"`
+}
+
+// FeedsConfig - настройки управления лентами через API.
+type FeedsConfig struct {
+	StorePath     string `yaml:"store_path"`
+	ValidateFetch bool   `yaml:"validate_fetch"`
+}
+
+// StreamConfig - настройки SSE-потока новостей.
+type StreamConfig struct {
+	ReplaySize       int `yaml:"replay_size"`
+	MaxConnections   int `yaml:"max_connections"`
+	ClientBuffer     int `yaml:"client_buffer"`
+	HeartbeatSeconds int `yaml:"heartbeat_seconds"`
+}
+
+// WSConfig - настройки WebSocket-канала комментариев.
+type WSConfig struct {
+	MaxConnections int      `yaml:"max_connections"`
+	ClientBuffer   int      `yaml:"client_buffer"`
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+// GraphQLConfig - настройки эндпоинта /graphql.
+type GraphQLConfig struct {
+	Enabled               bool   `yaml:"enabled"`
+	MaxDepth              int    `yaml:"max_depth"`
+	MaxComplexity         int    `yaml:"max_complexity"`
+	PersistedQueriesDir   string `yaml:"persisted_queries_dir"`
+	AllowArbitraryQueries bool   `yaml:"allow_arbitrary_queries"`
+}
+
+// OpenAPIConfig - настройки документации и проверки запросов по спецификации.
+type OpenAPIConfig struct {
+	ValidateRequests bool `yaml:"validate_requests"`
+}
+
+// VersionsConfig - сроки вывода из эксплуатации /api/v1 (даты YYYY-MM-DD).
+type VersionsConfig struct {
+	V1DeprecatedAt string `yaml:"v1_deprecated_at"`
+	V1Sunset       string `yaml:"v1_sunset"`
+}
+
+// CompressionConfig - сжатие ответов HTTP API.
+type CompressionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MinSize - минимальный размер тела в байтах, с которого ответ сжимается.
+	MinSize int `yaml:"min_size"`
+}
+
+// BatchConfig - ограничения POST /api/v2/news/batch.
+type BatchConfig struct {
+	MaxIDs int `yaml:"max_ids"`
+}
+
+// PageConfig - составная страница GET /api/v2/pages/{name}.
+type PageConfig struct {
+	Name string `yaml:"name"`
+	// CacheTTL - время жизни собранного ответа в секундах; 0 - без кэша.
+	CacheTTL int              `yaml:"cache_ttl"`
+	Parts    []PagePartConfig `yaml:"parts"`
+}
+
+// PagePartConfig - часть составной страницы: вид запроса и его параметры.
+type PagePartConfig struct {
+	Name      string            `yaml:"name"`
+	Kind      string            `yaml:"kind"`
+	Of        string            `yaml:"of"`
+	TimeoutMs int               `yaml:"timeout_ms"`
+	Params    map[string]string `yaml:"params"`
+}
+
+// AuthConfig - токены доступа и соответствующие им роли.
+type AuthConfig struct {
+	Tokens []AuthToken `yaml:"tokens"`
+}
+
+type AuthToken struct {
+	Token string `yaml:"token"`
+	Role  string `yaml:"role"`
+}
+
 type KafkaConfig struct {
 	Brokers        []string          `yaml:"brokers"`
 	Topics         KafkaTopics       `yaml:"topics"`
@@ -73,11 +179,24 @@ type DBConfig struct {
 
 // Config основная конфигурация.
 type Config struct {
-	App     AppConfig     `yaml:"app"`
-	HTTP    HTTPConfig    `yaml:"http"`
-	Logging LoggingConfig `yaml:"logging"`
-	Kafka   KafkaConfig   `yaml:"kafka"`
-	Routes  []Route       `yaml:"routes"`
+	App         AppConfig         `yaml:"app"`
+	HTTP        HTTPConfig        `yaml:"http"`
+	GRPC        GRPCConfig        `yaml:"grpc"`
+	Logging     LoggingConfig     `yaml:"logging"`
+	Kafka       KafkaConfig       `yaml:"kafka"`
+	Routes      []Route           `yaml:"routes"`
+	Censor      CensorConfig      `yaml:"censor"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Search      SearchConfig      `yaml:"search"`
+	Feeds       FeedsConfig       `yaml:"feeds"`
+	Stream      StreamConfig      `yaml:"stream"`
+	WS          WSConfig          `yaml:"ws"`
+	GraphQL     GraphQLConfig     `yaml:"graphql"`
+	OpenAPI     OpenAPIConfig     `yaml:"openapi"`
+	Versions    VersionsConfig    `yaml:"versions"`
+	Compression CompressionConfig `yaml:"compression"`
+	Batch       BatchConfig       `yaml:"batch"`
+	Pages       []PageConfig      `yaml:"pages"`
 }
 
 func (c *Config) GetAppName() string {
@@ -100,6 +219,75 @@ func (c *Config) GetWriteTimeout() time.Duration {
 	return time.Duration(c.App.WriteTimeout) * time.Second
 }
 
+func (c *Config) GetCensorTimeout() time.Duration {
+	return time.Duration(c.Censor.TimeoutMs) * time.Millisecond
+}
+
+func (c *Config) GetSearchTimeout() time.Duration {
+	if c.Search.BackendTimeoutMs <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.Search.BackendTimeoutMs) * time.Millisecond
+}
+
+func (c *Config) GetSourcesReloadInterval() time.Duration {
+	if c.App.SourcesReloadInterval <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(c.App.SourcesReloadInterval) * time.Second
+}
+
+func (c *Config) GetFeedCacheTTL() time.Duration {
+	if c.App.FeedCacheTTL <= 0 {
+		return time.Minute
+	}
+	return time.Duration(c.App.FeedCacheTTL) * time.Second
+}
+
+func (c *Config) GetStreamHeartbeat() time.Duration {
+	if c.Stream.HeartbeatSeconds <= 0 {
+		return 15 * time.Second
+	}
+	return time.Duration(c.Stream.HeartbeatSeconds) * time.Second
+}
+
+// GetV1Deprecation возвращает даты объявления устаревшим и отключения /api/v1.
+// Незаданная дата возвращается нулевым временем.
+func (c *Config) GetV1Deprecation() (since, sunset time.Time, err error) {
+	if c.Versions.V1DeprecatedAt != "" {
+		if since, err = time.Parse(time.DateOnly, c.Versions.V1DeprecatedAt); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid versions.v1_deprecated_at: %w", err)
+		}
+	}
+	if c.Versions.V1Sunset != "" {
+		if sunset, err = time.Parse(time.DateOnly, c.Versions.V1Sunset); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid versions.v1_sunset: %w", err)
+		}
+	}
+	return since, sunset, nil
+}
+
+// GetAuthTokens возвращает соответствие токен -> роль. Пустые токены пропускаются.
+func (c *Config) GetAuthTokens() map[string]string {
+	tokens := make(map[string]string, len(c.Auth.Tokens))
+	for _, t := range c.Auth.Tokens {
+		if t.Token != "" {
+			tokens[t.Token] = t.Role
+		}
+	}
+	return tokens
+}
+
+// GetRouteURL возвращает base_url сервиса по имени маршрута.
+func (c *Config) GetRouteURL(name string) (string, bool) {
+	for _, route := range c.Routes {
+		if route.Name == name {
+			return route.BaseURL, true
+		}
+	}
+	return "", false
+}
+
 // LoadConfig загружает конфиг из файла.
 func LoadConfig(configPath string) (*Config, error) {
 	if configPath == "" {
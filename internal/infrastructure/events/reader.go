@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Reader читает топик событий в собственной группе потребителей экземпляра
+// шлюза, чтобы каждый экземпляр получал все события (fan-out), а не их часть.
+// Реализует kafkawrapper.Cons.
+type Reader struct {
+	reader *kafka.Reader
+}
+
+// NewReader создает читателя топика. К имени группы добавляется имя хоста,
+// чтобы экземпляры шлюза не делили партиции между собой.
+func NewReader(brokers []string, topic, group string) *Reader {
+	host, err := os.Hostname()
+	if err != nil {
+		host = fmt.Sprintf("pid%d", os.Getpid())
+	}
+	return &Reader{reader: kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     brokers,
+		GroupID:     group + "-" + host,
+		Topic:       topic,
+		StartOffset: kafka.LastOffset,
+	})}
+}
+
+// GetMessages читает следующее сообщение топика.
+func (r *Reader) GetMessages(ctx context.Context) (kafka.Message, error) {
+	return r.reader.ReadMessage(ctx)
+}
+
+// Close закрывает соединение с Kafka.
+func (r *Reader) Close() error {
+	return r.reader.Close()
+}
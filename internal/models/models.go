@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type NewsFullDetailed struct {
 	NewsID      int       `json:"news_ id"`
@@ -12,6 +15,8 @@ type NewsFullDetailed struct {
 	Source      string    `json:"source"`
 	Link        string    `json:"link"`
 	Tag         []string  `json:"tag"`
+	// CommentCount заполняется шлюзом по include=comment_count.
+	CommentCount *int `json:"comment_count,omitempty"`
 }
 
 type NewsShortDetailed struct {
@@ -20,12 +25,68 @@ type NewsShortDetailed struct {
 	Description string `json:"description"`
 }
 
+// NewsSparse - новость с выборкой полей (fields=, view=short).
+// Невыбранные поля равны nil и в ответ не попадают; news_ id есть всегда.
+type NewsSparse struct {
+	NewsID       int        `json:"news_ id"`
+	Title        *string    `json:"title,omitempty"`
+	Description  *string    `json:"description,omitempty"`
+	Content      *string    `json:"content,omitempty"`
+	Author       *string    `json:"author,omitempty"`
+	PublishedAt  *time.Time `json:"published_at,omitempty"`
+	Source       *string    `json:"source,omitempty"`
+	Link         *string    `json:"link,omitempty"`
+	Tag          []string   `json:"tag,omitempty"`
+	CommentCount *int       `json:"comment_count,omitempty"`
+}
+
+// ModerationState - состояние модерации комментария.
+type ModerationState string
+
+const (
+	ModerationPending  ModerationState = "pending"
+	ModerationApproved ModerationState = "approved"
+	ModerationRejected ModerationState = "rejected"
+)
+
 type Comment struct {
-	CommentID int       `json:"coment_id"`
-	NewsID    int       `json:"news_id"`
-	Message   string    `json:"message"`
-	CreatedAt time.Time `json:"created_at"`
-	Cens      bool      `json:"cens"`
+	CommentID  int             `json:"coment_id"`
+	NewsID     int             `json:"news_id"`
+	ParentID   *int            `json:"parent_id,omitempty"`
+	Message    string          `json:"message"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Cens       bool            `json:"cens"`
+	Moderation ModerationState `json:"moderation"`
+}
+
+// State возвращает состояние модерации.
+// Если сервис комментариев его не прислал, состояние выводится из флага Cens.
+func (c Comment) State() ModerationState {
+	switch c.Moderation {
+	case ModerationPending, ModerationApproved, ModerationRejected:
+		return c.Moderation
+	}
+	if c.Cens {
+		return ModerationRejected
+	}
+	return ModerationApproved
+}
+
+// CommentNode - комментарий с глубиной вложенности и ответами на него.
+type CommentNode struct {
+	Comment
+	Depth   int            `json:"depth"`
+	Replies []*CommentNode `json:"replies,omitempty"`
+}
+
+// CommentThread - страница ветки комментариев.
+// Пагинация применяется к верхнему уровню ветки: к корневым комментариям новости
+// или к прямым ответам на ParentID.
+type CommentThread struct {
+	ListEnvelope[*CommentNode]
+	NewsID   int    `json:"news_id"`
+	ParentID *int   `json:"parent_id,omitempty"`
+	View     string `json:"view"`
 }
 
 type DetailedResponse struct {
@@ -33,31 +94,311 @@ type DetailedResponse struct {
 	Error error       `json:"error"`
 }
 
+// FinalResponse - новость с деревом комментариев.
+// News - NewsFullDetailed или NewsSparse при выборке полей.
 type FinalResponse struct {
-	News     string `json:"news"`
-	Comments string `json:"comments"`
+	News     any            `json:"news"`
+	Comments []*CommentNode `json:"comments"`
+}
+
+// ListEnvelope - стандартная обертка списочных ответов шлюза.
+// Page не заполняется при курсорной пагинации, Next и Prev - ссылки на соседние страницы.
+type ListEnvelope[T any] struct {
+	Items      []T    `json:"items"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	HasNext    bool   `json:"has_next"`
+	Next       string `json:"next,omitempty"`
+	Prev       string `json:"prev,omitempty"`
+}
+
+// NewListEnvelope собирает обертку для постраничного списка.
+func NewListEnvelope[T any](items []T, page, limit, total int) ListEnvelope[T] {
+	if items == nil {
+		items = []T{}
+	}
+	totalPages := 0
+	if limit > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	return ListEnvelope[T]{
+		Items:      items,
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+		HasNext:    page > 0 && page < totalPages,
+	}
 }
 
 // Request/Response структуры для Kafka
 type NewsListRequest struct {
-	Page   int    `json:"page"`
-	Limit  int    `json:"limit"`
-	Filter string `json:"filter"`
+	Page    int            `json:"page,omitempty"`
+	Limit   int            `json:"limit"`
+	Filter  string         `json:"filter"`
+	Sources []string       `json:"sources,omitempty"`
+	After   *NewsCursorKey `json:"after,omitempty"`
+	Before  *NewsCursorKey `json:"before,omitempty"`
+}
+
+// NewsCursorKey - ключ сортировки ленты (published_at DESC, news_id DESC).
+// After запрашивает новости старше ключа, Before - новее ключа.
+type NewsCursorKey struct {
+	PublishedAt time.Time `json:"published_at"`
+	NewsID      int       `json:"news_id"`
+}
+
+// NewsListResponse - ответ сервиса новостей на запрос списка.
+type NewsListResponse struct {
+	Items []NewsFullDetailed `json:"items"`
+	Total int                `json:"total"`
 }
 type NewsDetailRequest struct {
 	NewsID int `json:"news_id"`
 }
 type CommentsRequest struct {
-	NewsID int `json:"news_id"`
+	NewsID  int   `json:"news_id,omitempty"`
+	NewsIDs []int `json:"news_ids,omitempty"`
 }
 type AddCommentRequest struct {
-	NewsID  int    `json:"news_id"`
-	Content string `json:"content"`
+	NewsID   int    `json:"news_id"`
+	ParentID *int   `json:"parent_id,omitempty"`
+	Content  string `json:"content"`
+	Pending  bool   `json:"pending,omitempty"`
 }
+
+// FilterContentRequest - выборка новостей по фильтру с пагинацией.
+// DateFrom и DateTo задают полуинтервал публикации в RFC3339 (UTC).
 type FilterContentRequest struct {
-	Content string `json:"content"`
+	NewsFilter
+	DateFrom string `json:"date_from,omitempty"`
+	DateTo   string `json:"date_to,omitempty"`
+	Page     int    `json:"page"`
+	Limit    int    `json:"limit"`
 }
+
+// FilterDateRequest - выборка новостей за полуинтервал [StartDate, EndDate).
+// Даты передаются в RFC3339 в UTC.
 type FilterDateRequest struct {
 	StartDate string `json:"start_date"`
 	EndDate   string `json:"end_date"`
+	Page      int    `json:"page"`
+	Limit     int    `json:"limit"`
+}
+
+// SearchRequest - запрос полнотекстового поиска к сервису новостей.
+type SearchRequest struct {
+	Query string `json:"query"`
+	Page  int    `json:"page"`
+	Limit int    `json:"limit"`
+}
+
+// SearchResponse - ответ сервиса новостей на поисковый запрос.
+type SearchResponse struct {
+	Items []SearchHit `json:"items"`
+	Total int         `json:"total"`
+}
+
+// SearchHit - найденная новость с оценкой релевантности и подсветкой.
+type SearchHit struct {
+	News      NewsFullDetailed `json:"news"`
+	Score     float64          `json:"score"`
+	Highlight SearchHighlight  `json:"highlight"`
+}
+
+// SearchHitSparse - результат поиска с выборкой полей новости, оценки и подсветки.
+type SearchHitSparse struct {
+	News      NewsSparse       `json:"news"`
+	Score     *float64         `json:"score,omitempty"`
+	Highlight *SearchHighlight `json:"highlight,omitempty"`
+}
+
+// SearchHighlight - фрагменты с подсвеченными совпадениями (<mark>).
+type SearchHighlight struct {
+	Title   string `json:"title,omitempty"`
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Режимы сопоставления тегов.
+const (
+	MatchAny = "any"
+	MatchAll = "all"
+)
+
+// Варианты сортировки ленты. Префикс "-" означает обратный порядок.
+const (
+	SortPublishedDesc = "-published_at"
+	SortPublishedAsc  = "published_at"
+	SortTitleAsc      = "title"
+	SortTitleDesc     = "-title"
+)
+
+const (
+	maxFilterValues      = 20
+	maxFilterValueLength = 100
+)
+
+// FilterSet - включаемые и исключаемые значения одного поля фильтра.
+type FilterSet struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Empty сообщает, что по полю не задано ни одного условия.
+func (f FilterSet) Empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// NewsFilter - типизированный фильтр ленты новостей.
+// Match применяется к включаемым тегам: any - хотя бы один, all - все сразу.
+type NewsFilter struct {
+	Categories FilterSet `json:"categories"`
+	Authors    FilterSet `json:"authors"`
+	Tags       FilterSet `json:"tags"`
+	Sources    FilterSet `json:"sources"`
+	Match      string    `json:"match"`
+	Sort       string    `json:"sort"`
+}
+
+// Validate проверяет фильтр. knownSources - имена источников из конфигурации.
+func (f NewsFilter) Validate(knownSources []string) error {
+	if f.Match != MatchAny && f.Match != MatchAll {
+		return fmt.Errorf("invalid match value %q: expected %s or %s", f.Match, MatchAny, MatchAll)
+	}
+	switch f.Sort {
+	case SortPublishedDesc, SortPublishedAsc, SortTitleAsc, SortTitleDesc:
+	default:
+		return fmt.Errorf("invalid sort value %q", f.Sort)
+	}
+
+	sets := map[string]FilterSet{
+		"category": f.Categories,
+		"author":   f.Authors,
+		"tags":     f.Tags,
+		"source":   f.Sources,
+	}
+	for name, set := range sets {
+		if err := set.validate(name); err != nil {
+			return err
+		}
+	}
+
+	known := make(map[string]bool, len(knownSources))
+	for _, s := range knownSources {
+		known[s] = true
+	}
+	for _, s := range append(f.Sources.Include, f.Sources.Exclude...) {
+		if !known[s] {
+			return fmt.Errorf("unknown source %q", s)
+		}
+	}
+	return nil
+}
+
+func (f FilterSet) validate(name string) error {
+	if len(f.Include)+len(f.Exclude) > maxFilterValues {
+		return fmt.Errorf("too many %s values: at most %d allowed", name, maxFilterValues)
+	}
+	included := make(map[string]bool, len(f.Include))
+	for _, v := range f.Include {
+		if v == "" || len(v) > maxFilterValueLength {
+			return fmt.Errorf("invalid %s value %q", name, v)
+		}
+		included[v] = true
+	}
+	for _, v := range f.Exclude {
+		if v == "" || len(v) > maxFilterValueLength {
+			return fmt.Errorf("invalid %s value %q", name, v)
+		}
+		if included[v] {
+			return fmt.Errorf("%s value %q is both included and excluded", name, v)
+		}
+	}
+	return nil
+}
+
+// Source - источник новостей (RSS-лента) с метаданными от сервиса новостей.
+type Source struct {
+	Name         string     `json:"name"`
+	URL          string     `json:"url"`
+	LastFetch    *time.Time `json:"last_fetch,omitempty"`
+	ArticleCount int        `json:"article_count"`
+}
+
+// SourceStatsRequest - запрос статистики по источникам к сервису новостей.
+type SourceStatsRequest struct {
+	Sources []string `json:"sources"`
+}
+
+// SourceStatsResponse - статистика сервиса новостей по источникам.
+type SourceStatsResponse struct {
+	Items []SourceStats `json:"items"`
+}
+
+type SourceStats struct {
+	Name         string     `json:"name"`
+	LastFetch    *time.Time `json:"last_fetch,omitempty"`
+	ArticleCount int        `json:"article_count"`
+}
+
+// Действия над лентами в событиях FeedChangeEvent.
+const (
+	FeedActionAdd    = "add"
+	FeedActionRemove = "remove"
+)
+
+// FeedChangeEvent - событие изменения списка лент для агрегатора новостей.
+type FeedChangeEvent struct {
+	Action    string    `json:"action"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// NewsBatchRequest - тело POST /api/v2/news/batch.
+type NewsBatchRequest struct {
+	IDs []int `json:"ids"`
+}
+
+// NewsBatch - ответ пакетного запроса новостей: новость или ошибка по каждому id.
+type NewsBatch struct {
+	Items map[int]NewsBatchItem `json:"items"`
+}
+
+// NewsBatchItem - результат по одной новости пакета.
+// News - NewsFullDetailed или NewsSparse при выборке полей.
+type NewsBatchItem struct {
+	News  any         `json:"news,omitempty"`
+	Error *BatchError `json:"error,omitempty"`
+}
+
+// CompositePage - ответ составной страницы GET /api/v2/pages/{name}.
+// Parts - данные успешных частей, Errors - ошибки остальных.
+type CompositePage struct {
+	Name        string                 `json:"name"`
+	Parts       map[string]any         `json:"parts"`
+	Errors      map[string]*BatchError `json:"errors,omitempty"`
+	Partial     bool                   `json:"partial"`
+	GeneratedAt time.Time              `json:"generated_at"`
+}
+
+// BatchError - ошибка по элементу пакетного запроса или части составной страницы.
+type BatchError struct {
+	Status int    `json:"status"`
+	Title  string `json:"title"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewCommentRequest - тело запроса POST /api/v2/news/{id}/comments.
+type NewCommentRequest struct {
+	ParentID *int   `json:"parent_id,omitempty"`
+	Content  string `json:"content"`
+}
+
+// AddFeedRequest - тело запроса на добавление ленты.
+type AddFeedRequest struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
 }
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"apigateway/internal/problem"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	swaggerFiles "github.com/swaggo/files"
+)
+
+// HandleSpec Враппер для хендлера документа OpenAPI.
+// Документ строится при первом запросе, когда все маршруты уже зарегистрированы.
+func HandleSpec(reg *Registry, info Info) http.HandlerFunc {
+	var (
+		once sync.Once
+		body []byte
+		err  error
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			body, err = json.Marshal(reg.Document(info))
+		})
+		if err != nil {
+			problem.Render(w, r, problem.Wrap(problem.Internal, "", err, "Failed to build OpenAPI document"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}
+}
+
+// swaggerInitializer настраивает Swagger UI на документ шлюза.
+const swaggerInitializer = `window.onload = function () {
+  window.ui = SwaggerUIBundle({
+    url: %q,
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// HandleDocs Враппер для хендлера Swagger UI. Статика Swagger UI встроена
+// в бинарник, prefix - путь страницы с завершающим "/".
+func HandleDocs(prefix, specURL string) http.Handler {
+	files := http.StripPrefix(prefix, http.FileServer(swaggerFiles.HTTP))
+	initializer := fmt.Sprintf(swaggerInitializer, specURL)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == prefix+"swagger-initializer.js" {
+			w.Header().Set("Content-Type", "application/javascript")
+			w.Write([]byte(initializer))
+			return
+		}
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,254 @@
+package openapi
+
+import (
+	"apigateway/internal/problem"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Route - маршрут шлюза: путь с параметрами {name}, хендлер и описание операций.
+// Каждая операция регистрируется в http.ServeMux шаблоном "METHOD path".
+type Route struct {
+	Path    string
+	Handler http.Handler
+	Ops     []Op
+	// Prefix - маршрут обслуживает и все вложенные пути (Path оканчивается на /).
+	Prefix bool
+	// Aliases - дополнительные пути того же хендлера, не попадающие в документ.
+	Aliases []string
+	// Middleware применяются поверх проверки запроса, в порядке объявления.
+	Middleware []func(http.Handler) http.Handler
+}
+
+// Op - описание операции маршрута для одного HTTP-метода.
+type Op struct {
+	Method string
+	// Handler - хендлер операции; если не задан, используется Route.Handler.
+	Handler     http.Handler
+	Summary     string
+	Description string
+	Tags        []string
+	Params      []Param
+	// Body - пример типа JSON-тела запроса.
+	Body any
+	// Response - тип поля data ответа RenderJSON. Для ContentType, отличного
+	// от application/json, или при Raw ответ описывается без обертки.
+	Response    any
+	ContentType string
+	Raw         bool
+	// Proto - имя сообщения protobuf, которым ответ отдается
+	// при Accept: application/x-protobuf.
+	Proto  string
+	Status int
+	Errors []int
+	// Auth - операция требует токена (Authorization: Bearer).
+	Auth       bool
+	Deprecated bool
+}
+
+// Param - параметр запроса.
+type Param struct {
+	Name        string
+	In          string
+	Description string
+	Required    bool
+	Schema      *Schema
+}
+
+// Query - параметр строки запроса.
+func Query(name string, schema *Schema, description string) Param {
+	return Param{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// Path - параметр пути ({name} в шаблоне маршрута), всегда обязательный.
+func Path(name string, schema *Schema, description string) Param {
+	return Param{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+// Require помечает параметр обязательным.
+func (p Param) Require() Param {
+	p.Required = true
+	return p
+}
+
+// Registry - реестр маршрутов шлюза.
+type Registry struct {
+	routes []Route
+}
+
+// NewRegistry создает пустой реестр.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Handle добавляет маршрут.
+func (r *Registry) Handle(route Route) {
+	r.routes = append(r.routes, route)
+}
+
+// Routes возвращает зарегистрированные маршруты.
+func (r *Registry) Routes() []Route {
+	return r.routes
+}
+
+// Mount регистрирует операции маршрутов в mux. Если validate задан,
+// запросы проверяются по описанию операции (см. Validate).
+// Методы без операции mux отклоняет сам: 405 с заголовком Allow.
+func (r *Registry) Mount(mux *http.ServeMux, validate bool) {
+	for _, route := range r.routes {
+		for _, op := range route.Ops {
+			handler := op.Handler
+			if handler == nil {
+				handler = route.Handler
+			}
+			if validate {
+				handler = Validate(op)(handler)
+			}
+			for i := len(route.Middleware) - 1; i >= 0; i-- {
+				handler = route.Middleware[i](handler)
+			}
+			for _, path := range append([]string{route.Path}, route.Aliases...) {
+				mux.Handle(op.Method+" "+muxPath(path, route.Prefix), handler)
+			}
+		}
+	}
+}
+
+// muxPath ограничивает путь с завершающим / точным совпадением, если маршрут
+// не обслуживает вложенные пути.
+func muxPath(path string, prefix bool) string {
+	if !prefix && strings.HasSuffix(path, "/") {
+		return path + "{$}"
+	}
+	return path
+}
+
+// Document строит документ OpenAPI по зарегистрированным маршрутам.
+func (r *Registry) Document(info Info) *Document {
+	gen := newSchemas()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearer": {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+	errSchema := gen.of(problem.Problem{})
+
+	for _, route := range r.routes {
+		item := PathItem{}
+		for _, op := range route.Ops {
+			item[strings.ToLower(op.Method)] = r.operation(gen, route.Path, op, errSchema)
+		}
+		doc.Paths[route.Path] = &item
+	}
+	doc.Components.Schemas = gen.components
+	return doc
+}
+
+func (r *Registry) operation(gen *schemas, path string, op Op, errSchema *Schema) *Operation {
+	o := &Operation{
+		OperationID: operationID(op.Method, path),
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Responses:   make(map[string]Response),
+		Deprecated:  op.Deprecated,
+	}
+	for _, p := range op.Params {
+		o.Parameters = append(o.Parameters, Parameter{
+			Name:        p.Name,
+			In:          p.In,
+			Description: p.Description,
+			Required:    p.Required,
+			Schema:      p.Schema,
+		})
+	}
+	if op.Body != nil {
+		o.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: gen.of(op.Body)}},
+		}
+	}
+	if op.Auth {
+		o.Security = []map[string][]string{{"bearer": {}}}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	resp := Response{Description: http.StatusText(status)}
+	contentType := op.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	switch {
+	case op.Response == nil:
+	case contentType != "application/json":
+		resp.Content = map[string]MediaType{contentType: {Schema: gen.of(op.Response)}}
+	case op.Raw:
+		resp.Content = map[string]MediaType{contentType: {Schema: gen.of(op.Response)}}
+	default:
+		data := envelope(gen.of(op.Response))
+		resp.Content = map[string]MediaType{contentType: {Schema: data}, "application/msgpack": {Schema: data}}
+		if op.Proto != "" {
+			resp.Content["application/x-protobuf"] = MediaType{Schema: &Schema{Type: "string", Format: "binary", Description: op.Proto}}
+		}
+	}
+	o.Responses[strconv.Itoa(status)] = resp
+
+	errs := op.Errors
+	if len(op.Params) > 0 || op.Body != nil {
+		errs = append([]int{http.StatusBadRequest}, errs...)
+	}
+	for _, code := range uniqueSorted(errs) {
+		o.Responses[strconv.Itoa(code)] = Response{
+			Description: http.StatusText(code),
+			Content:     map[string]MediaType{problem.ContentType: {Schema: errSchema}},
+		}
+	}
+	return o
+}
+
+// envelope оборачивает схему данных в ответ RenderJSON.
+func envelope(data *Schema) *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status": Enum("", "success"),
+			"data":   data,
+		},
+		Required: []string{"status", "data"},
+	}
+}
+
+// operationID строит идентификатор операции из метода и пути: GET /newslist/filtered/ -> getNewslistFiltered.
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '.' || r == '-' || r == '_' || r == '{' || r == '}'
+	}) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func uniqueSorted(codes []int) []int {
+	seen := make(map[int]bool, len(codes))
+	var out []int
+	for _, c := range codes {
+		if !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
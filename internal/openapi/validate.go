@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"apigateway/internal/problem"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const maxValidatedBody = 1 << 20
+
+// Validate - middleware проверки запроса по описанию операции:
+// обязательность и типы параметров, допустимые значения и корректность JSON-тела.
+func Validate(op Op) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := validateRequest(r, op); err != nil {
+				problem.Respond(w, r, problem.Validation, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func validateRequest(r *http.Request, op Op) error {
+	query := r.URL.Query()
+	for _, p := range op.Params {
+		var values []string
+		switch p.In {
+		case "query":
+			values = query[p.Name]
+		case "header":
+			values = r.Header.Values(p.Name)
+		case "path":
+			if v := r.PathValue(p.Name); v != "" {
+				values = []string{v}
+			}
+		default:
+			continue
+		}
+		if len(values) == 0 {
+			if p.Required {
+				return fmt.Errorf("missing required parameter %q", p.Name)
+			}
+			continue
+		}
+
+		schema := p.Schema
+		if schema != nil && schema.Type == "array" {
+			schema = schema.Items
+		} else if len(values) > 1 {
+			return fmt.Errorf("parameter %q must be set once", p.Name)
+		}
+		for _, v := range values {
+			if err := checkValue(v, schema); err != nil {
+				return fmt.Errorf("invalid %s parameter: %w", p.Name, err)
+			}
+		}
+	}
+
+	if op.Body != nil && r.Body != nil {
+		raw, err := io.ReadAll(io.LimitReader(r.Body, maxValidatedBody+1))
+		if err != nil {
+			return fmt.Errorf("failed to read request body")
+		}
+		if len(raw) > maxValidatedBody {
+			return fmt.Errorf("request body is too large")
+		}
+		if !json.Valid(raw) {
+			return fmt.Errorf("request body must be valid JSON")
+		}
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+	}
+	return nil
+}
+
+func checkValue(v string, s *Schema) error {
+	if s == nil {
+		return nil
+	}
+	switch s.Type {
+	case "integer":
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", v)
+		}
+		return checkRange(float64(n), s)
+	case "number":
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", v)
+		}
+		return checkRange(n, s)
+	case "boolean":
+		if _, err := strconv.ParseBool(v); err != nil {
+			return fmt.Errorf("%q is not a boolean", v)
+		}
+	case "string":
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, any(v)) {
+			return fmt.Errorf("%q is not one of %v", v, s.Enum)
+		}
+		if s.MaxLength != nil && len([]rune(v)) > *s.MaxLength {
+			return fmt.Errorf("value is longer than %d characters", *s.MaxLength)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				return fmt.Errorf("%q is not an RFC 3339 date-time", v)
+			}
+		}
+	}
+	return nil
+}
+
+func checkRange(n float64, s *Schema) error {
+	if s.Minimum != nil && n < *s.Minimum {
+		return fmt.Errorf("must be at least %v", *s.Minimum)
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		return fmt.Errorf("must be at most %v", *s.Maximum)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package pagination
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Направление перехода по курсору.
+const (
+	DirectionNext = "next"
+	DirectionPrev = "prev"
+)
+
+// Ошибки разбора курсора.
+var (
+	// ErrInvalidCursor возвращается для поврежденных или подделанных курсоров.
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrCursorMismatch возвращается для курсора, выданного для других фильтров.
+	ErrCursorMismatch = errors.New("cursor does not match the query")
+)
+
+// Cursor - позиция в ленте новостей, упорядоченной по published_at и news_id.
+// Filter - хеш фильтров запроса, для которого выдан курсор (см. FilterHash).
+type Cursor struct {
+	PublishedAt time.Time `json:"p"`
+	NewsID      int       `json:"i"`
+	Direction   string    `json:"d"`
+	Filter      string    `json:"f,omitempty"`
+}
+
+// FilterHash возвращает хеш нормализованных фильтров ленты: порядок
+// и повторы значений не влияют на результат.
+func FilterHash(filter url.Values) string {
+	normalized := make(url.Values, len(filter))
+	for name, values := range filter {
+		if len(values) == 0 {
+			continue
+		}
+		values = slices.Clone(values)
+		slices.Sort(values)
+		normalized[name] = slices.Compact(values)
+	}
+	sum := sha256.Sum256([]byte(normalized.Encode()))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// Signer подписывает курсоры, чтобы клиент не мог их подделать.
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner создает подписчик курсоров. При пустом секрете генерируется
+// случайный ключ, и выданные курсоры перестают действовать после перезапуска.
+func NewSigner(secret string) (*Signer, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate cursor secret: %w", err)
+		}
+	}
+	return &Signer{secret: key}, nil
+}
+
+// Encode сериализует и подписывает курсор.
+func (s *Signer) Encode(c Cursor) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(s.sign(payload)), nil
+}
+
+// Decode проверяет подпись, разбирает курсор и сверяет его с хешем
+// фильтров текущего запроса filter.
+func (s *Signer) Decode(raw, filter string) (Cursor, error) {
+	enc := base64.RawURLEncoding
+	body, sig, ok := strings.Cut(raw, ".")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	payload, err := enc.DecodeString(body)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	mac, err := enc.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.sign(payload)) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.Direction != DirectionNext && c.Direction != DirectionPrev {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.Filter != filter {
+		return Cursor{}, ErrCursorMismatch
+	}
+	return c, nil
+}
+
+func (s *Signer) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write(payload)
+	return h.Sum(nil)
+}
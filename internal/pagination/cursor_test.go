@@ -0,0 +1,73 @@
+package pagination
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	s, err := NewSigner("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := FilterHash(url.Values{"source": {"habr"}})
+	want := Cursor{PublishedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), NewsID: 42, Direction: DirectionNext, Filter: filter}
+
+	raw, err := s.Encode(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Decode(raw, filter)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !got.PublishedAt.Equal(want.PublishedAt) || got.NewsID != want.NewsID || got.Direction != want.Direction {
+		t.Errorf("Decode() = %+v, want %+v", got, want)
+	}
+
+	other := FilterHash(url.Values{"source": {"lenta"}})
+	if _, err := s.Decode(raw, other); !errors.Is(err, ErrCursorMismatch) {
+		t.Errorf("Decode() with other filter error = %v, want ErrCursorMismatch", err)
+	}
+	if _, err := s.Decode(raw, FilterHash(nil)); !errors.Is(err, ErrCursorMismatch) {
+		t.Errorf("Decode() without filter error = %v, want ErrCursorMismatch", err)
+	}
+}
+
+func TestDecodeRejectsForgedCursor(t *testing.T) {
+	s, _ := NewSigner("secret")
+	raw, _ := s.Encode(Cursor{NewsID: 1, Direction: DirectionPrev})
+	body, sig, _ := strings.Cut(raw, ".")
+
+	other, _ := NewSigner("other")
+	forged, _ := other.Encode(Cursor{NewsID: 1, Direction: DirectionPrev})
+	_, forgedSig, _ := strings.Cut(forged, ".")
+
+	for _, bad := range []string{"", "nodot", body + "." + forgedSig, "x" + body + "." + sig, body + "." + sig + "x"} {
+		if _, err := s.Decode(bad, ""); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Decode(%q) error = %v, want ErrInvalidCursor", bad, err)
+		}
+	}
+
+	noDirection, _ := s.Encode(Cursor{NewsID: 1})
+	if _, err := s.Decode(noDirection, ""); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Decode() without direction error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestFilterHash(t *testing.T) {
+	a := FilterHash(url.Values{"source": {"lenta", "habr"}})
+	b := FilterHash(url.Values{"source": {"habr", "lenta", "habr"}})
+	if a != b {
+		t.Errorf("FilterHash depends on order or repeats: %q != %q", a, b)
+	}
+	if FilterHash(url.Values{"source": nil}) != FilterHash(nil) {
+		t.Error("empty filter differs from no filter")
+	}
+	if a == FilterHash(url.Values{"source": {"habr"}}) {
+		t.Error("different filters have the same hash")
+	}
+}
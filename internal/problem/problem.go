@@ -0,0 +1,129 @@
+// Package problem описывает ошибки HTTP API в формате
+// application/problem+json (RFC 7807).
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ContentType - тип содержимого ответа с ошибкой.
+const ContentType = "application/problem+json"
+
+// typePrefix - префикс URI типа ошибки.
+const typePrefix = "urn:gonews:problem:"
+
+// Problem - тело ответа с ошибкой.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Upstream - сервис, из-за которого запрос не выполнен.
+	Upstream string `json:"upstream,omitempty"`
+}
+
+// Kind - вид ошибки: тип, заголовок и HTTP-статус.
+type Kind struct {
+	Type   string
+	Title  string
+	Status int
+}
+
+// Виды ошибок API.
+var (
+	Validation       = Kind{typePrefix + "validation", "Invalid request", http.StatusBadRequest}
+	Unauthorized     = Kind{typePrefix + "unauthorized", "Authentication required", http.StatusUnauthorized}
+	Forbidden        = Kind{typePrefix + "forbidden", "Insufficient permissions", http.StatusForbidden}
+	NotFound         = Kind{typePrefix + "not-found", "Resource not found", http.StatusNotFound}
+	MethodNotAllowed = Kind{typePrefix + "method-not-allowed", "Method not allowed", http.StatusMethodNotAllowed}
+	NotAcceptable    = Kind{typePrefix + "not-acceptable", "Response format not acceptable", http.StatusNotAcceptable}
+	Conflict         = Kind{typePrefix + "conflict", "Resource already exists", http.StatusConflict}
+	Unprocessable    = Kind{typePrefix + "unprocessable", "Request cannot be processed", http.StatusUnprocessableEntity}
+	Internal         = Kind{typePrefix + "internal", "Internal server error", http.StatusInternalServerError}
+	Upstream         = Kind{typePrefix + "upstream", "Upstream service failure", http.StatusBadGateway}
+	Unavailable      = Kind{typePrefix + "unavailable", "Service unavailable", http.StatusServiceUnavailable}
+	Timeout          = Kind{typePrefix + "upstream-timeout", "Upstream service timeout", http.StatusGatewayTimeout}
+)
+
+var kinds = []Kind{Validation, Unauthorized, Forbidden, NotFound, MethodNotAllowed, NotAcceptable, Conflict, Unprocessable, Internal, Upstream, Unavailable, Timeout}
+
+// KindOf возвращает вид ошибки для HTTP-статуса. Неизвестные статусы 4xx
+// считаются ошибкой запроса, остальные - внутренней ошибкой.
+func KindOf(status int) Kind {
+	for _, k := range kinds {
+		if k.Status == status {
+			return k
+		}
+	}
+	if status >= 400 && status < 500 {
+		return Kind{Type: "about:blank", Title: http.StatusText(status), Status: status}
+	}
+	return Internal
+}
+
+// Error - типизированная ошибка API.
+type Error struct {
+	Kind     Kind
+	Detail   string
+	Upstream string
+	Err      error
+}
+
+// New создает ошибку вида kind с описанием detail.
+func New(kind Kind, detail string) *Error {
+	return &Error{Kind: kind, Detail: detail}
+}
+
+// Newf создает ошибку вида kind с форматированным описанием.
+func Newf(kind Kind, format string, args ...any) *Error {
+	return &Error{Kind: kind, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Wrap создает ошибку вида kind по причине err от сервиса upstream.
+func Wrap(kind Kind, upstream string, err error, detail string) *Error {
+	return &Error{Kind: kind, Detail: detail, Upstream: upstream, Err: err}
+}
+
+func (e *Error) Error() string {
+	msg := e.Kind.Title
+	if e.Detail != "" {
+		msg = e.Detail
+	}
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// From приводит произвольную ошибку к *Error; неизвестные ошибки считаются внутренними
+// и их текст клиенту не отдается.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return &Error{Kind: Internal, Err: err}
+}
+
+// Problem возвращает тело ответа для запроса r.
+func (e *Error) Problem(r *http.Request) Problem {
+	p := Problem{
+		Type:     e.Kind.Type,
+		Title:    e.Kind.Title,
+		Status:   e.Kind.Status,
+		Detail:   e.Detail,
+		Upstream: e.Upstream,
+	}
+	if r != nil {
+		p.Instance = r.URL.RequestURI()
+	}
+	return p
+}
@@ -0,0 +1,97 @@
+package problem
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// Render отвечает ошибкой err в формате application/problem+json.
+// request_id берется из заголовка X-Request-ID ответа (см. RequestIDMiddleware).
+// Если ответ уже начат, ошибка только логируется: второй ответ испортил бы первый.
+func Render(w http.ResponseWriter, r *http.Request, err error) {
+	e := From(err)
+	g := guardOf(w)
+	if g != nil && g.started {
+		log.Printf("problem: response already started, dropping error: %v\n", err)
+		return
+	}
+	if e.Kind.Status >= http.StatusInternalServerError && e.Err != nil {
+		log.Printf("problem: %s %s: %v\n", r.Method, r.URL.Path, e)
+	}
+
+	p := e.Problem(r)
+	p.RequestID = w.Header().Get("X-Request-ID")
+	body, mErr := json.Marshal(p)
+	if mErr != nil {
+		body = []byte(`{"type":"about:blank","title":"Internal server error","status":500}`)
+		p.Status = http.StatusInternalServerError
+	}
+
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", ContentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	w.Write(body)
+	if g != nil {
+		g.failed = true
+	}
+}
+
+// Respond - сокращение для Render(w, r, New(kind, detail)).
+func Respond(w http.ResponseWriter, r *http.Request, kind Kind, detail string) {
+	Render(w, r, New(kind, detail))
+}
+
+// Guard обеспечивает единственный ответ на запрос: после Render последующие
+// записи хендлера отбрасываются, повторный WriteHeader игнорируется.
+func Guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&guardWriter{ResponseWriter: w}, r)
+	})
+}
+
+type guardWriter struct {
+	http.ResponseWriter
+	started bool
+	failed  bool
+}
+
+func (g *guardWriter) WriteHeader(statusCode int) {
+	if g.started {
+		return
+	}
+	// Информационные ответы (1xx) не завершают заголовки
+	if statusCode >= 200 || statusCode == http.StatusSwitchingProtocols {
+		g.started = true
+	}
+	g.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (g *guardWriter) Write(b []byte) (int, error) {
+	if g.failed {
+		return len(b), nil
+	}
+	g.started = true
+	return g.ResponseWriter.Write(b)
+}
+
+// Unwrap дает http.ResponseController доступ к исходному writer (Flush, Hijack).
+func (g *guardWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// guardOf ищет guardWriter в цепочке оберток writer.
+func guardOf(w http.ResponseWriter) *guardWriter {
+	for {
+		switch v := w.(type) {
+		case *guardWriter:
+			return v
+		case interface{ Unwrap() http.ResponseWriter }:
+			w = v.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package search
+
+import (
+	"apigateway/internal/models"
+	"strings"
+	"unicode"
+)
+
+// span - позиция терма в исходном тексте.
+type span struct {
+	term       string
+	start, end int
+}
+
+// Tokenize разбивает текст на термы в нижнем регистре.
+func Tokenize(text string) []string {
+	spans := tokenSpans(text)
+	terms := make([]string, len(spans))
+	for i, s := range spans {
+		terms[i] = s.term
+	}
+	return terms
+}
+
+func tokenSpans(text string) []span {
+	var spans []span
+	start := -1
+	for i, r := range text {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			spans = append(spans, span{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return spans
+}
+
+// Веса полей при подсчете релевантности.
+var fieldWeights = map[string]float64{
+	"title":       3,
+	"description": 2,
+	"content":     1,
+}
+
+// document - новость, разобранная на термы по полям.
+type document struct {
+	news   models.NewsFullDetailed
+	fields map[string][]string
+	freq   map[string]map[string]int
+}
+
+func newDocument(n models.NewsFullDetailed) *document {
+	d := &document{
+		news: n,
+		fields: map[string][]string{
+			"title":       Tokenize(n.Title),
+			"description": Tokenize(n.Description),
+			"content":     Tokenize(n.Content),
+		},
+		freq: make(map[string]map[string]int),
+	}
+	for field, terms := range d.fields {
+		for _, t := range terms {
+			if d.freq[t] == nil {
+				d.freq[t] = make(map[string]int)
+			}
+			d.freq[t][field]++
+		}
+	}
+	return d
+}
+
+func (d *document) has(term string) bool {
+	return len(d.freq[term]) > 0
+}
+
+// hasPhrase ищет последовательность термов в пределах одного поля.
+func (d *document) hasPhrase(terms []string) bool {
+	for _, fieldTerms := range d.fields {
+		for i := 0; i+len(terms) <= len(fieldTerms); i++ {
+			matched := true
+			for j, t := range terms {
+				if fieldTerms[i+j] != t {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// uniqueTerms возвращает множество термов документа.
+func (d *document) uniqueTerms() []string {
+	terms := make([]string, 0, len(d.freq))
+	for t := range d.freq {
+		terms = append(terms, t)
+	}
+	return terms
+}
@@ -0,0 +1,90 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	snippetLength  = 200
+	snippetContext = 60
+)
+
+// Highlight оборачивает вхождения термов в <mark>, экранируя остальной текст.
+func Highlight(text string, terms []string) string {
+	return highlightRange(text, 0, len(text), termSet(terms))
+}
+
+// Snippet возвращает фрагмент текста вокруг первого вхождения термов
+// с подсветкой. Если вхождений нет, возвращается начало текста.
+func Snippet(text string, terms []string) string {
+	set := termSet(terms)
+	first := -1
+	for _, s := range tokenSpans(text) {
+		if set[s.term] {
+			first = s.start
+			break
+		}
+	}
+
+	start := 0
+	if first > snippetContext {
+		start = wordBoundary(text, first-snippetContext)
+	}
+	end := len(text)
+	if end-start > snippetLength {
+		end = wordBoundary(text, start+snippetLength)
+	}
+
+	snippet := highlightRange(text, start, end, set)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+func highlightRange(text string, start, end int, set map[string]bool) string {
+	var b strings.Builder
+	pos := start
+	for _, s := range tokenSpans(text[start:end]) {
+		if !set[s.term] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos : start+s.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[start+s.start : start+s.end]))
+		b.WriteString("</mark>")
+		pos = start + s.end
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	return strings.TrimSpace(b.String())
+}
+
+// wordBoundary сдвигает позицию к ближайшему пробелу, чтобы не резать слова и руны.
+func wordBoundary(text string, pos int) int {
+	if pos >= len(text) {
+		return len(text)
+	}
+	if i := strings.IndexByte(text[pos:], ' '); i >= 0 && i < snippetContext {
+		return pos + i
+	}
+	for pos < len(text) && !isRuneStart(text[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func termSet(terms []string) map[string]bool {
+	set := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		set[t] = true
+	}
+	return set
+}
@@ -0,0 +1,160 @@
+package search
+
+import (
+	"apigateway/internal/models"
+	"math"
+	"sort"
+	"sync"
+)
+
+const defaultMaxDocuments = 5000
+
+// Index - локальный полнотекстовый индекс новостей, прошедших через шлюз.
+// Используется, когда сервис новостей не поддерживает поиск.
+// Методы безопасны для вызова на nil-индексе.
+type Index struct {
+	mu      sync.RWMutex
+	docs    map[int]*document
+	order   []int
+	df      map[string]int
+	maxDocs int
+}
+
+// NewIndex создает индекс, хранящий не более maxDocs последних новостей.
+func NewIndex(maxDocs int) *Index {
+	if maxDocs <= 0 {
+		maxDocs = defaultMaxDocuments
+	}
+	return &Index{
+		docs:    make(map[int]*document),
+		df:      make(map[string]int),
+		maxDocs: maxDocs,
+	}
+}
+
+// Add добавляет или обновляет новости в индексе.
+func (i *Index) Add(items ...models.NewsFullDetailed) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, n := range items {
+		if _, ok := i.docs[n.NewsID]; ok {
+			i.remove(n.NewsID)
+		}
+		d := newDocument(n)
+		i.docs[n.NewsID] = d
+		i.order = append(i.order, n.NewsID)
+		for _, t := range d.uniqueTerms() {
+			i.df[t]++
+		}
+	}
+	for len(i.docs) > i.maxDocs {
+		i.remove(i.order[0])
+	}
+}
+
+// remove удаляет документ. Вызывается под блокировкой записи.
+func (i *Index) remove(id int) {
+	d, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	for _, t := range d.uniqueTerms() {
+		if i.df[t]--; i.df[t] <= 0 {
+			delete(i.df, t)
+		}
+	}
+	delete(i.docs, id)
+	for k, v := range i.order {
+		if v == id {
+			i.order = append(i.order[:k], i.order[k+1:]...)
+			break
+		}
+	}
+}
+
+// Search возвращает страницу результатов, упорядоченных по релевантности,
+// и общее число найденных новостей.
+func (i *Index) Search(q Query, page, limit int) ([]models.SearchHit, int) {
+	if i == nil {
+		return nil, 0
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	var hits []models.SearchHit
+	for _, d := range i.docs {
+		if !q.Match(d) {
+			continue
+		}
+		hits = append(hits, models.SearchHit{News: d.news, Score: i.score(d, q.Terms)})
+	}
+	sortHits(hits)
+
+	total := len(hits)
+	start := (page - 1) * limit
+	if start >= total {
+		return []models.SearchHit{}, total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	return hits[start:end], total
+}
+
+// score считает TF-IDF с учетом весов полей.
+func (i *Index) score(d *document, terms []string) float64 {
+	n := float64(len(i.docs))
+	var score float64
+	for _, t := range terms {
+		df := i.df[t]
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + n/float64(df))
+		for field, tf := range d.freq[t] {
+			score += fieldWeights[field] * (1 + math.Log(float64(tf))) * idf
+		}
+	}
+	return math.Round(score*1000) / 1000
+}
+
+// sortHits упорядочивает результаты по убыванию релевантности,
+// при равенстве - по свежести.
+func sortHits(hits []models.SearchHit) {
+	sort.SliceStable(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		if !hits[a].News.PublishedAt.Equal(hits[b].News.PublishedAt) {
+			return hits[a].News.PublishedAt.After(hits[b].News.PublishedAt)
+		}
+		return hits[a].News.NewsID > hits[b].News.NewsID
+	})
+}
+
+// Rank упорядочивает результаты сервиса новостей и добавляет подсветку.
+func Rank(hits []models.SearchHit, q Query) {
+	sortHits(hits)
+	for k := range hits {
+		Decorate(&hits[k], q)
+	}
+}
+
+// Decorate заполняет подсветку заголовка и сниппет, если их нет.
+func Decorate(hit *models.SearchHit, q Query) {
+	if hit.Highlight.Title == "" {
+		hit.Highlight.Title = Highlight(hit.News.Title, q.Terms)
+	}
+	if hit.Highlight.Snippet == "" {
+		text := hit.News.Content
+		if text == "" {
+			text = hit.News.Description
+		}
+		hit.Highlight.Snippet = Snippet(text, q.Terms)
+	}
+}
@@ -0,0 +1,274 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const maxQueryLength = 256
+
+// ErrEmptyQuery возвращается для пустого поискового запроса.
+var ErrEmptyQuery = errors.New("empty search query")
+
+// Query - разобранный поисковый запрос.
+// Поддерживаются фразы в кавычках, операторы AND, OR, NOT, отрицание через "-"
+// и группировка скобками. Соседние термы без оператора объединяются через AND.
+type Query struct {
+	Raw   string
+	Terms []string
+	root  node
+}
+
+// Match сообщает, удовлетворяет ли документ запросу.
+func (q Query) Match(d *document) bool {
+	return q.root.match(d)
+}
+
+type node interface {
+	match(d *document) bool
+}
+
+type termNode struct{ term string }
+
+func (n termNode) match(d *document) bool { return d.has(n.term) }
+
+type phraseNode struct{ terms []string }
+
+func (n phraseNode) match(d *document) bool { return d.hasPhrase(n.terms) }
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(d *document) bool { return n.left.match(d) && n.right.match(d) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(d *document) bool { return n.left.match(d) || n.right.match(d) }
+
+type notNode struct{ child node }
+
+func (n notNode) match(d *document) bool { return !n.child.match(d) }
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokPhrase
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// Parse разбирает строку поискового запроса.
+func Parse(raw string) (Query, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Query{}, ErrEmptyQuery
+	}
+	if len(raw) > maxQueryLength {
+		return Query{}, fmt.Errorf("search query is longer than %d bytes", maxQueryLength)
+	}
+
+	tokens, err := lex(raw)
+	if err != nil {
+		return Query{}, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return Query{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return Query{}, fmt.Errorf("unexpected token at position %d", p.pos)
+	}
+	return Query{Raw: raw, Terms: positiveTerms(root, false), root: root}, nil
+}
+
+func lex(raw string) ([]token, error) {
+	var tokens []token
+	runes := []rune(raw)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokNot})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated phrase")
+			}
+			tokens = append(tokens, token{kind: tokPhrase, value: string(runes[i+1 : end])})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			switch word {
+			case "AND":
+				tokens = append(tokens, token{kind: tokAnd})
+			case "OR":
+				tokens = append(tokens, token{kind: tokOr})
+			case "NOT":
+				tokens = append(tokens, token{kind: tokNot})
+			default:
+				tokens = append(tokens, token{kind: tokWord, value: word})
+			}
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+const maxNesting = 16
+
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind != tokOr {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t, ok := p.peek()
+		if !ok || t.kind == tokOr || t.kind == tokRParen {
+			return left, nil
+		}
+		if t.kind == tokAnd {
+			p.pos++
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, errors.New("unexpected end of query")
+	}
+	if t.kind == tokNot {
+		p.pos++
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t, _ := p.peek()
+	p.pos++
+	switch t.kind {
+	case tokWord, tokPhrase:
+		return termsNode(Tokenize(t.value))
+	case tokLParen:
+		p.depth++
+		if p.depth > maxNesting {
+			return nil, errors.New("query is nested too deeply")
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next, ok := p.peek(); !ok || next.kind != tokRParen {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		p.depth--
+		return inner, nil
+	default:
+		return nil, fmt.Errorf("unexpected operator at position %d", p.pos-1)
+	}
+}
+
+// termsNode превращает слово или фразу в узел запроса.
+// Слово вида "e-mail" распадается на несколько термов и ищется как фраза.
+func termsNode(terms []string) (node, error) {
+	switch {
+	case len(terms) == 0:
+		return nil, errors.New("query term has no searchable characters")
+	case len(terms) == 1:
+		return termNode{terms[0]}, nil
+	default:
+		return phraseNode{terms}, nil
+	}
+}
+
+// positiveTerms собирает термы вне отрицаний - по ним считается релевантность
+// и строится подсветка.
+func positiveTerms(n node, negated bool) []string {
+	switch v := n.(type) {
+	case termNode:
+		if !negated {
+			return []string{v.term}
+		}
+	case phraseNode:
+		if !negated {
+			return v.terms
+		}
+	case andNode:
+		return append(positiveTerms(v.left, negated), positiveTerms(v.right, negated)...)
+	case orNode:
+		return append(positiveTerms(v.left, negated), positiveTerms(v.right, negated)...)
+	case notNode:
+		return positiveTerms(v.child, !negated)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"apigateway/internal/models"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTerms(t *testing.T) {
+	q, err := Parse(`(Kafka OR rust) "consumer groups" -java`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"kafka", "rust", "consumer", "groups"}; !slices.Equal(q.Terms, want) {
+		t.Errorf("Terms = %v, want %v", q.Terms, want)
+	}
+
+	if _, err := Parse("   "); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("Parse(blank) error = %v, want ErrEmptyQuery", err)
+	}
+	for _, raw := range []string{
+		`"unterminated`,
+		"(go OR rust",
+		"go)",
+		"go AND",
+		"OR go",
+		"!!!",
+		strings.Repeat("(", maxNesting+1) + "go" + strings.Repeat(")", maxNesting+1),
+		strings.Repeat("a", maxQueryLength+1),
+	} {
+		if _, err := Parse(raw); err == nil {
+			t.Errorf("Parse(%.20q) succeeded, want error", raw)
+		}
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	d := newDocument(models.NewsFullDetailed{
+		NewsID:  1,
+		Title:   "Kafka consumers in Go",
+		Content: "Breaking news: consumer groups rebalance",
+	})
+	match := func(raw string) bool {
+		q, err := Parse(raw)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", raw, err)
+		}
+		return q.Match(d)
+	}
+
+	for _, raw := range []string{"KAFKA go", "kafka OR rust", "kafka NOT rust", `"breaking news"`, "(rust OR go) consumers"} {
+		if !match(raw) {
+			t.Errorf("%q does not match", raw)
+		}
+	}
+	for _, raw := range []string{"kafka rust", "kafka -go", `"news breaking"`} {
+		if match(raw) {
+			t.Errorf("%q matches", raw)
+		}
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	idx := NewIndex(2)
+	idx.Add(
+		models.NewsFullDetailed{NewsID: 1, Title: "Go 1.24 released"},
+		models.NewsFullDetailed{NewsID: 2, Title: "Kafka and Go", Content: "Go go go"},
+		models.NewsFullDetailed{NewsID: 3, Title: "Rust news"},
+	)
+
+	q, _ := Parse("go")
+	hits, total := idx.Search(q, 1, 10)
+	// Новость 1 вытеснена: индекс хранит две последние.
+	if total != 1 || len(hits) != 1 || hits[0].News.NewsID != 2 {
+		t.Fatalf("Search(go) = %v, total %d; want news 2 only", hits, total)
+	}
+	Decorate(&hits[0], q)
+	if !strings.Contains(hits[0].Highlight.Title, "<mark>Go</mark>") {
+		t.Errorf("highlighted title = %q", hits[0].Highlight.Title)
+	}
+
+	idx.Add(models.NewsFullDetailed{NewsID: 2, Title: "Kafka only"})
+	if hits, _ := idx.Search(q, 1, 10); len(hits) != 0 {
+		t.Errorf("updated news still found by old terms: %v", hits)
+	}
+
+	var empty *Index
+	empty.Add(models.NewsFullDetailed{NewsID: 1})
+	if hits, total := empty.Search(q, 1, 10); len(hits) != 0 || total != 0 {
+		t.Errorf("nil index Search() = %v, %d", hits, total)
+	}
+}
+
+func TestHighlightEscapes(t *testing.T) {
+	got := Highlight("<b>Go</b> & gophers", []string{"go"})
+	if want := "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; &amp; gophers"; got != want {
+		t.Errorf("Highlight() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,192 @@
+package sources
+
+import (
+	"apigateway/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrExists возвращается при добавлении источника с занятым именем или URL.
+	ErrExists = errors.New("source already exists")
+	// ErrNotFound возвращается при удалении неизвестного источника.
+	ErrNotFound = errors.New("source not found")
+)
+
+// Registry - актуальный список источников новостей.
+// Складывается из feed_urls конфигурации и изменений, сделанных через API
+// управления лентами. Изменения через API сохраняются в Store.
+type Registry struct {
+	mu      sync.RWMutex
+	base    []models.Source
+	overlay Overlay
+	store   *Store
+	feeds   []models.Source
+}
+
+// NewRegistry создает реестр с начальным списком источников.
+// Если store не nil, из него загружаются ранее сделанные изменения.
+func NewRegistry(feeds []models.Source, store *Store) (*Registry, error) {
+	r := &Registry{store: store}
+	if store != nil {
+		overlay, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		r.overlay = overlay
+	}
+	r.Replace(feeds)
+	return r, nil
+}
+
+// List возвращает копию списка источников.
+func (r *Registry) List() []models.Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.feeds)
+}
+
+// Names возвращает имена источников.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.feeds))
+	for i, f := range r.feeds {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Base возвращает источники из конфигурации без учета изменений через API.
+func (r *Registry) Base() []models.Source {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.base)
+}
+
+// Replace заменяет список источников из конфигурации.
+func (r *Registry) Replace(feeds []models.Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.base = slices.Clone(feeds)
+	r.rebuild()
+}
+
+// Add добавляет источник и сохраняет изменение.
+func (r *Registry) Add(src models.Source) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, f := range r.feeds {
+		if f.Name == src.Name || f.URL == src.URL {
+			return fmt.Errorf("%w: %s", ErrExists, f.Name)
+		}
+	}
+
+	overlay := r.overlay.clone()
+	overlay.Removed = slices.DeleteFunc(overlay.Removed, func(name string) bool { return name == src.Name })
+	if !slices.ContainsFunc(r.base, func(f models.Source) bool { return f == src }) {
+		overlay.Added = append(overlay.Added, src)
+	}
+	return r.commit(overlay)
+}
+
+// Remove удаляет источник по имени и сохраняет изменение.
+func (r *Registry) Remove(name string) (models.Source, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := slices.IndexFunc(r.feeds, func(f models.Source) bool { return f.Name == name })
+	if idx < 0 {
+		return models.Source{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	removed := r.feeds[idx]
+
+	overlay := r.overlay.clone()
+	overlay.Added = slices.DeleteFunc(overlay.Added, func(f models.Source) bool { return f.Name == name })
+	if slices.ContainsFunc(r.base, func(f models.Source) bool { return f.Name == name }) {
+		overlay.Removed = append(overlay.Removed, name)
+	}
+	if err := r.commit(overlay); err != nil {
+		return models.Source{}, err
+	}
+	return removed, nil
+}
+
+// commit сохраняет изменения и применяет их. Вызывается под блокировкой записи.
+func (r *Registry) commit(overlay Overlay) error {
+	if r.store != nil {
+		if err := r.store.Save(overlay); err != nil {
+			return err
+		}
+	}
+	r.overlay = overlay
+	r.rebuild()
+	return nil
+}
+
+// rebuild пересчитывает итоговый список. Вызывается под блокировкой записи.
+func (r *Registry) rebuild() {
+	feeds := make([]models.Source, 0, len(r.base)+len(r.overlay.Added))
+	for _, f := range r.base {
+		if !slices.Contains(r.overlay.Removed, f.Name) {
+			feeds = append(feeds, f)
+		}
+	}
+	for _, f := range r.overlay.Added {
+		if !slices.ContainsFunc(feeds, func(e models.Source) bool { return e.Name == f.Name }) {
+			feeds = append(feeds, f)
+		}
+	}
+	r.feeds = feeds
+}
+
+// Loader читает список источников из файла конфигурации.
+type Loader func(path string) ([]models.Source, error)
+
+// WatchConfig периодически проверяет время изменения файла конфигурации
+// и при изменении перечитывает из него список источников.
+// Завершается по отмене контекста.
+func (r *Registry) WatchConfig(ctx context.Context, path string, interval time.Duration, load Loader, log *slog.Logger) {
+	var lastMod time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastMod = info.ModTime()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			log.Warn("Failed to stat config file", "path", path, "error", err)
+			continue
+		}
+		if !info.ModTime().After(lastMod) {
+			continue
+		}
+		lastMod = info.ModTime()
+
+		feeds, err := load(path)
+		if err != nil {
+			log.Error("Failed to reload feed list", "path", path, "error", err)
+			continue
+		}
+		if slices.Equal(feeds, r.Base()) {
+			continue
+		}
+		r.Replace(feeds)
+		log.Info("Feed list reloaded", "sources", len(feeds))
+	}
+}
@@ -0,0 +1,81 @@
+package sources
+
+import (
+	"apigateway/internal/models"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+var (
+	bbc   = models.Source{Name: "bbc", URL: "https://bbc.example/rss"}
+	habr  = models.Source{Name: "habr", URL: "https://habr.example/rss"}
+	lenta = models.Source{Name: "lenta", URL: "https://lenta.example/rss"}
+)
+
+func assertNames(t *testing.T, r *Registry, want ...string) {
+	t.Helper()
+	if got := r.Names(); !slices.Equal(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+func TestRegistryAddRemove(t *testing.T) {
+	r, err := NewRegistry([]models.Source{bbc, habr}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Add(models.Source{Name: "bbc", URL: "https://other.example/rss"}); !errors.Is(err, ErrExists) {
+		t.Errorf("Add() with a taken name: error = %v, want ErrExists", err)
+	}
+	if err := r.Add(models.Source{Name: "bbc2", URL: bbc.URL}); !errors.Is(err, ErrExists) {
+		t.Errorf("Add() with a taken URL: error = %v, want ErrExists", err)
+	}
+	if err := r.Add(lenta); err != nil {
+		t.Fatal(err)
+	}
+	assertNames(t, r, "bbc", "habr", "lenta")
+
+	if _, err := r.Remove("unknown"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Remove(unknown) error = %v, want ErrNotFound", err)
+	}
+	removed, err := r.Remove("bbc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != bbc {
+		t.Errorf("Remove() = %+v, want %+v", removed, bbc)
+	}
+	assertNames(t, r, "habr", "lenta")
+
+	// Откат удаления (см. rollbackFeedChange) возвращает ленту из конфигурации.
+	if err := r.Add(removed); err != nil {
+		t.Fatal(err)
+	}
+	assertNames(t, r, "bbc", "habr", "lenta")
+}
+
+func TestRegistryStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "feeds", "overlay.json"))
+	r, err := NewRegistry([]models.Source{bbc, habr}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Add(lenta); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Remove("bbc"); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, err := NewRegistry([]models.Source{bbc, habr}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNames(t, reloaded, "habr", "lenta")
+	if got := reloaded.Base(); len(got) != 2 {
+		t.Errorf("Base() = %v, want the configured feeds", got)
+	}
+}
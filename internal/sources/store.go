@@ -0,0 +1,75 @@
+package sources
+
+import (
+	"apigateway/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Overlay - изменения списка источников, сделанные через API:
+// добавленные ленты и имена удаленных лент из конфигурации.
+type Overlay struct {
+	Added   []models.Source `json:"added"`
+	Removed []string        `json:"removed"`
+}
+
+func (o Overlay) clone() Overlay {
+	return Overlay{Added: slices.Clone(o.Added), Removed: slices.Clone(o.Removed)}
+}
+
+// Store хранит Overlay в JSON-файле.
+type Store struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewStore создает файловое хранилище изменений списка источников.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load читает изменения. Отсутствие файла не считается ошибкой.
+func (s *Store) Load() (Overlay, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Overlay{}, nil
+	}
+	if err != nil {
+		return Overlay{}, fmt.Errorf("failed to read feed store: %w", err)
+	}
+	var overlay Overlay
+	if err := json.Unmarshal(raw, &overlay); err != nil {
+		return Overlay{}, fmt.Errorf("failed to parse feed store: %w", err)
+	}
+	return overlay, nil
+}
+
+// Save атомарно записывает изменения через временный файл.
+func (s *Store) Save(overlay Overlay) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := json.MarshalIndent(overlay, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode feed store: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create feed store directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return fmt.Errorf("failed to write feed store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write feed store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package sources
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	maxNameLength    = 100
+	feedFetchLimit   = 1 << 20
+	feedFetchTimeout = 5 * time.Second
+)
+
+// ErrInvalidFeed возвращается, если лента не прошла проверку.
+var ErrInvalidFeed = errors.New("invalid feed")
+
+// Validator проверяет новые ленты перед добавлением.
+type Validator struct {
+	client *http.Client
+	fetch  bool
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// NewValidator создает валидатор. Имя хоста ленты разрешается, и ленты
+// с loopback, link-local и частными адресами отклоняются. Если fetch включен,
+// лента скачивается и проверяется, что это RSS, Atom или RDF документ;
+// соединения с непубличными адресами запрещены и после редиректа.
+func NewValidator(fetch bool) *Validator {
+	dialer := &net.Dialer{Timeout: feedFetchTimeout, Control: dialPublicOnly}
+	return &Validator{
+		client: &http.Client{
+			Timeout:   feedFetchTimeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		},
+		fetch:  fetch,
+		lookup: net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// Validate проверяет имя и URL ленты.
+func (v *Validator) Validate(ctx context.Context, name, rawURL string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxNameLength {
+		return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidFeed, maxNameLength)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidFeed)
+	}
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: url must point to a public host", ErrInvalidFeed)
+	}
+	if err := v.checkHost(ctx, host); err != nil {
+		return err
+	}
+	if !v.fetch {
+		return nil
+	}
+	return v.checkDocument(ctx, u.String())
+}
+
+// checkHost проверяет, что хост - публичный адрес или имя, все адреса
+// которого публичные. Агрегатор скачивает ленту сам, поэтому проверка
+// нужна и без скачивания при добавлении.
+func (v *Validator) checkHost(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if !publicIP(ip) {
+			return fmt.Errorf("%w: url must point to a public host", ErrInvalidFeed)
+		}
+		return nil
+	}
+	addrs, err := v.lookup(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: failed to resolve host %s", ErrInvalidFeed, host)
+	}
+	for _, addr := range addrs {
+		if !publicIP(addr.IP) {
+			return fmt.Errorf("%w: url must point to a public host", ErrInvalidFeed)
+		}
+	}
+	return nil
+}
+
+// publicIP сообщает, что адрес не ведет в сеть шлюза: не loopback,
+// не link-local, не частный и не unspecified.
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() &&
+		!ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast()
+}
+
+// dialPublicOnly запрещает соединения с непубличными адресами. Проверяется
+// адрес после разрешения имени, поэтому DNS не позволяет обойти проверку.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("address %s is not public", host)
+	}
+	return nil
+}
+
+// checkDocument скачивает ленту и проверяет корневой элемент XML.
+func (v *Validator) checkDocument(ctx context.Context, feedURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml, text/xml")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: failed to fetch feed: %v", ErrInvalidFeed, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: feed responded with status %d", ErrInvalidFeed, resp.StatusCode)
+	}
+
+	dec := xml.NewDecoder(io.LimitReader(resp.Body, feedFetchLimit))
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: document is not an RSS or Atom feed", ErrInvalidFeed)
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			switch strings.ToLower(start.Name.Local) {
+			case "rss", "feed", "rdf":
+				return nil
+			default:
+				return fmt.Errorf("%w: unexpected root element <%s>", ErrInvalidFeed, start.Name.Local)
+			}
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+)
+
+// testValidator - валидатор без скачивания лент с фиксированным DNS.
+func testValidator(hosts map[string][]string) *Validator {
+	v := NewValidator(false)
+	v.lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		var addrs []net.IPAddr
+		for _, ip := range hosts[host] {
+			addrs = append(addrs, net.IPAddr{IP: net.ParseIP(ip)})
+		}
+		if len(addrs) == 0 {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return addrs, nil
+	}
+	return v
+}
+
+func TestValidateAcceptsPublicFeeds(t *testing.T) {
+	v := testValidator(map[string][]string{"feeds.example.com": {"93.184.216.34", "2606:2800:220:1::1"}})
+	for _, rawURL := range []string{"https://feeds.example.com/rss", "http://93.184.216.34/rss"} {
+		if err := v.Validate(context.Background(), "example", rawURL); err != nil {
+			t.Errorf("Validate(%q) error = %v", rawURL, err)
+		}
+	}
+}
+
+func TestValidateRejectsInternalHosts(t *testing.T) {
+	v := testValidator(map[string][]string{
+		"intranet.example.com": {"10.0.0.7"},
+		"metadata.example.com": {"169.254.169.254"},
+		"mixed.example.com":    {"93.184.216.34", "127.0.0.1"},
+	})
+	for _, rawURL := range []string{
+		"http://localhost:8080/rss",
+		"http://feeds.localhost/rss",
+		"http://LOCALHOST./rss",
+		"http://127.0.0.1/rss",
+		"http://[::1]/rss",
+		"http://192.168.1.1/rss",
+		"http://0.0.0.0/rss",
+		"http://[::ffff:127.0.0.1]/rss",
+		"http://intranet.example.com/rss",
+		"http://metadata.example.com/latest/meta-data",
+		"http://mixed.example.com/rss",
+		"http://unknown.example.com/rss",
+	} {
+		if err := v.Validate(context.Background(), "internal", rawURL); !errors.Is(err, ErrInvalidFeed) {
+			t.Errorf("Validate(%q) error = %v, want ErrInvalidFeed", rawURL, err)
+		}
+	}
+}
+
+func TestValidateNameAndScheme(t *testing.T) {
+	v := testValidator(map[string][]string{"example.com": {"93.184.216.34"}})
+	ctx := context.Background()
+	if err := v.Validate(ctx, " ", "https://example.com/rss"); !errors.Is(err, ErrInvalidFeed) {
+		t.Errorf("blank name: error = %v", err)
+	}
+	if err := v.Validate(ctx, strings.Repeat("n", maxNameLength+1), "https://example.com/rss"); !errors.Is(err, ErrInvalidFeed) {
+		t.Errorf("long name: error = %v", err)
+	}
+	if err := v.Validate(ctx, "rel", "/rss"); !errors.Is(err, ErrInvalidFeed) {
+		t.Errorf("relative url: error = %v", err)
+	}
+	if err := v.Validate(ctx, "ftp", "ftp://example.com/rss"); !errors.Is(err, ErrInvalidFeed) {
+		t.Errorf("ftp url: error = %v", err)
+	}
+}
+
+func TestDialPublicOnly(t *testing.T) {
+	if err := dialPublicOnly("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("public address refused: %v", err)
+	}
+	for _, addr := range []string{"127.0.0.1:80", "[::1]:80", "10.1.2.3:80", "172.16.0.1:80", "169.254.169.254:80", "[fe80::1]:80", "no-port"} {
+		if err := dialPublicOnly("tcp", addr, nil); err == nil {
+			t.Errorf("dialPublicOnly(%q) allowed the connection", addr)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package stream
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrTooManySubscribers возвращается при превышении лимита подключений.
+var ErrTooManySubscribers = errors.New("too many subscribers")
+
+// Event - событие с идентификатором, выведенным из данных. Идентификатор
+// не зависит от экземпляра шлюза, поэтому Last-Event-ID работает и после
+// перезапуска или переподключения к другому экземпляру.
+type Event[T any] struct {
+	ID   string
+	Data T
+}
+
+// Subscription - подписка на события брокера.
+// Канал C закрывается при отписке или если подписчик не успевает
+// читать события (в этом случае Dropped возвращает true).
+type Subscription[T any] struct {
+	C       <-chan Event[T]
+	ch      chan Event[T]
+	match   func(T) bool
+	mu      sync.Mutex
+	dropped bool
+}
+
+// Dropped сообщает, что подписка была закрыта из-за переполнения буфера.
+func (s *Subscription[T]) Dropped() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Broker раздает события подписчикам и хранит последние события
+// для возобновления по Last-Event-ID.
+type Broker[T any] struct {
+	mu         sync.Mutex
+	id         func(T) string
+	replay     []Event[T]
+	replaySize int
+	subs       map[*Subscription[T]]struct{}
+	maxSubs    int
+	bufSize    int
+}
+
+const defaultBufSize = 16
+
+// NewBroker создает брокер с идентификаторами событий id, буфером повтора
+// replaySize, не более maxSubs подписчиками (0 - без ограничения) и буфером
+// bufSize событий на каждого подписчика.
+func NewBroker[T any](id func(T) string, replaySize, maxSubs, bufSize int) *Broker[T] {
+	if bufSize <= 0 {
+		bufSize = defaultBufSize
+	}
+	return &Broker[T]{
+		id:         id,
+		replaySize: replaySize,
+		subs:       make(map[*Subscription[T]]struct{}),
+		maxSubs:    maxSubs,
+		bufSize:    bufSize,
+	}
+}
+
+// Publish рассылает событие подписчикам.
+// Подписчики с заполненным буфером отключаются, чтобы не задерживать остальных.
+func (b *Broker[T]) Publish(data T) Event[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ev := Event[T]{ID: b.id(data), Data: data}
+
+	if b.replaySize > 0 {
+		if len(b.replay) == b.replaySize {
+			b.replay = append(b.replay[:0], b.replay[1:]...)
+		}
+		b.replay = append(b.replay, ev)
+	}
+
+	for s := range b.subs {
+		if s.match != nil && !s.match(data) {
+			continue
+		}
+		select {
+		case s.ch <- ev:
+		default:
+			s.mu.Lock()
+			s.dropped = true
+			s.mu.Unlock()
+			b.remove(s)
+		}
+	}
+	return ev
+}
+
+// Subscribe подписывает на события, удовлетворяющие match (nil - все события).
+// Возвращает также события из буфера повтора после события lastID; если его
+// в буфере уже нет, возвращается весь буфер.
+func (b *Broker[T]) Subscribe(lastID string, match func(T) bool) (*Subscription[T], []Event[T], error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.maxSubs > 0 && len(b.subs) >= b.maxSubs {
+		return nil, nil, ErrTooManySubscribers
+	}
+
+	ch := make(chan Event[T], b.bufSize)
+	s := &Subscription[T]{C: ch, ch: ch, match: match}
+	b.subs[s] = struct{}{}
+
+	var missed []Event[T]
+	if lastID != "" {
+		replay := b.replay
+		for i, ev := range replay {
+			if ev.ID == lastID {
+				replay = replay[i+1:]
+				break
+			}
+		}
+		for _, ev := range replay {
+			if match == nil || match(ev.Data) {
+				missed = append(missed, ev)
+			}
+		}
+	}
+	return s, missed, nil
+}
+
+// Unsubscribe отменяет подписку.
+func (b *Broker[T]) Unsubscribe(s *Subscription[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.remove(s)
+}
+
+// Subscribers возвращает число активных подписчиков.
+func (b *Broker[T]) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// remove удаляет подписчика и закрывает его канал. Вызывается под блокировкой.
+func (b *Broker[T]) remove(s *Subscription[T]) {
+	if _, ok := b.subs[s]; !ok {
+		return
+	}
+	delete(b.subs, s)
+	close(s.ch)
+}
@@ -0,0 +1,74 @@
+package stream
+
+import (
+	"strconv"
+	"testing"
+)
+
+func eventIDs[T any](events []Event[T]) []string {
+	ids := make([]string, len(events))
+	for i, ev := range events {
+		ids[i] = ev.ID
+	}
+	return ids
+}
+
+func TestBrokerReplay(t *testing.T) {
+	b := NewBroker(strconv.Itoa, 3, 0, 0)
+	for i := 1; i <= 4; i++ {
+		b.Publish(i)
+	}
+
+	_, missed, err := b.Subscribe("2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := eventIDs(missed); len(got) != 2 || got[0] != "3" || got[1] != "4" {
+		t.Errorf("replay after 2 = %v, want [3 4]", got)
+	}
+
+	// Событие 1 уже вытеснено из буфера, например после перезапуска шлюза.
+	_, missed, _ = b.Subscribe("1", nil)
+	if got := eventIDs(missed); len(got) != 3 {
+		t.Errorf("replay after evicted id = %v, want whole buffer", got)
+	}
+
+	_, missed, _ = b.Subscribe("4", nil)
+	if len(missed) != 0 {
+		t.Errorf("replay after last id = %v, want none", eventIDs(missed))
+	}
+
+	_, missed, _ = b.Subscribe("", nil)
+	if len(missed) != 0 {
+		t.Errorf("replay without Last-Event-ID = %v, want none", eventIDs(missed))
+	}
+
+	even := func(n int) bool { return n%2 == 0 }
+	_, missed, _ = b.Subscribe("1", even)
+	if got := eventIDs(missed); len(got) != 2 || got[0] != "2" || got[1] != "4" {
+		t.Errorf("filtered replay = %v, want [2 4]", got)
+	}
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	b := NewBroker(strconv.Itoa, 0, 1, 1)
+	sub, _, err := b.Subscribe("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := b.Subscribe("", nil); err != ErrTooManySubscribers {
+		t.Errorf("second Subscribe error = %v, want ErrTooManySubscribers", err)
+	}
+
+	b.Publish(1)
+	b.Publish(2)
+	if ev := <-sub.C; ev.ID != "1" {
+		t.Errorf("first event id = %q, want 1", ev.ID)
+	}
+	if _, ok := <-sub.C; ok || !sub.Dropped() {
+		t.Error("slow subscriber was not dropped")
+	}
+	if n := b.Subscribers(); n != 0 {
+		t.Errorf("Subscribers() = %d, want 0", n)
+	}
+}
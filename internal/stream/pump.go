@@ -0,0 +1,41 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+const pumpRetryDelay = time.Second
+
+// Pump читает сообщения из Kafka, разбирает их через decode и публикует
+// в брокер. Работает до отмены контекста.
+func Pump[T any](ctx context.Context, c kfk.Cons, b *Broker[T], decode func([]byte) ([]T, error), log *slog.Logger) {
+	for {
+		msg, err := c.GetMessages(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+				return
+			}
+			log.Error("Failed to read stream message from Kafka", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pumpRetryDelay):
+			}
+			continue
+		}
+
+		items, err := decode(msg.Value)
+		if err != nil {
+			log.Warn("Skipping malformed stream message", "error", err)
+			continue
+		}
+		for _, item := range items {
+			b.Publish(item)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Package graphql реализует GraphQL-эндпоинт шлюза поверх сервисов
+// новостей и комментариев.
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	gql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const maxBodySize = 1 << 20
+
+// request - тело GraphQL-запроса. Extensions.PersistedQuery позволяет
+// передать только идентификатор запроса из списка разрешенных.
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+	Extensions    struct {
+		PersistedQuery *struct {
+			Version    int    `json:"version"`
+			SHA256Hash string `json:"sha256Hash"`
+		} `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+// queryError - ошибка запроса до его выполнения.
+type queryError struct {
+	status  int
+	message string
+}
+
+func (e *queryError) Error() string { return e.message }
+
+// Handler Враппер для хендлера /graphql.
+// Принимает GET (только query) и POST с JSON-телом.
+func Handler(resolver *Resolver, allow *Allowlist, limits Limits) (http.HandlerFunc, error) {
+	schema, err := gql.ParseSchema(schemaSDL, resolver, gql.MaxDepth(limits.MaxDepth))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse graphql schema: %w", err)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		req, err := decodeRequest(w, r)
+		if err == nil {
+			err = resolveQuery(&req, allow)
+		}
+		if err == nil {
+			err = checkOperation(r.Method, req, limits)
+		}
+		if err != nil {
+			status := http.StatusBadRequest
+			if qerr, ok := err.(*queryError); ok {
+				status = qerr.status
+			}
+			writeResponse(w, &gql.Response{Errors: []*gqlerrors.QueryError{gqlerrors.Errorf("%s", err)}}, status)
+			return
+		}
+
+		ctx := resolver.withLoaders(r.Context())
+		writeResponse(w, schema.Exec(ctx, req.Query, req.OperationName, req.Variables), http.StatusOK)
+	}, nil
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request) (request, error) {
+	var req request
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+		if raw := query.Get("variables"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+				return req, &queryError{http.StatusBadRequest, "invalid variables parameter"}
+			}
+		}
+		if raw := query.Get("extensions"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &req.Extensions); err != nil {
+				return req, &queryError{http.StatusBadRequest, "invalid extensions parameter"}
+			}
+		}
+	case http.MethodPost:
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err := dec.Decode(&req); err != nil {
+			return req, &queryError{http.StatusBadRequest, "invalid request body"}
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, OPTIONS")
+		return req, &queryError{http.StatusMethodNotAllowed, "method not allowed"}
+	}
+	return req, nil
+}
+
+// resolveQuery подставляет текст persisted-запроса и проверяет список разрешенных.
+func resolveQuery(req *request, allow *Allowlist) error {
+	if pq := req.Extensions.PersistedQuery; pq != nil {
+		if req.Query != "" {
+			if queryHash(req.Query) != pq.SHA256Hash {
+				return &queryError{http.StatusBadRequest, "provided sha256Hash does not match query"}
+			}
+		} else {
+			query, ok := allow.Lookup(pq.SHA256Hash)
+			if !ok {
+				return &queryError{http.StatusNotFound, "PersistedQueryNotFound"}
+			}
+			req.Query = query
+		}
+	}
+	if req.Query == "" {
+		return &queryError{http.StatusBadRequest, "query is required"}
+	}
+	if !allow.Allowed(req.Query) {
+		return &queryError{http.StatusForbidden, "query is not in the persisted query allowlist"}
+	}
+	return nil
+}
+
+// checkOperation запрещает мутации через GET и слишком дорогие запросы.
+func checkOperation(method string, req request, limits Limits) error {
+	op, cost, err := analyze(req.Query, req.OperationName, req.Variables)
+	if err != nil {
+		return &queryError{http.StatusBadRequest, err.Error()}
+	}
+	if method == http.MethodGet && op != ast.Query {
+		return &queryError{http.StatusMethodNotAllowed, "only queries are allowed over GET"}
+	}
+	if limits.MaxComplexity > 0 && cost > limits.MaxComplexity {
+		return &queryError{http.StatusBadRequest, fmt.Sprintf("query complexity %d exceeds limit %d", cost, limits.MaxComplexity)}
+	}
+	return nil
+}
+
+func writeResponse(w http.ResponseWriter, resp *gql.Response, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
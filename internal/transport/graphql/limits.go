@@ -0,0 +1,75 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// Limits - ограничения на выполняемые запросы.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+// listSizes - ожидаемый размер списочных полей без аргумента limit.
+var listSizes = map[string]int{
+	"comments": 20,
+	"sources":  20,
+}
+
+// analyze находит операцию и оценивает ее стоимость: каждое поле стоит 1,
+// стоимость вложенной выборки умножается на limit поля или на ожидаемый
+// размер списка.
+func analyze(query, operationName string, vars map[string]any) (ast.Operation, int, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return "", 0, err
+	}
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return "", 0, fmt.Errorf("operation %q not found", operationName)
+	}
+	return op.Operation, selectionCost(op.SelectionSet, doc.Fragments, vars, map[string]bool{}), nil
+}
+
+func selectionCost(set ast.SelectionSet, fragments ast.FragmentDefinitionList, vars map[string]any, spreading map[string]bool) int {
+	total := 0
+	for _, sel := range set {
+		switch s := sel.(type) {
+		case *ast.Field:
+			total += 1 + fieldMultiplier(s, vars)*selectionCost(s.SelectionSet, fragments, vars, spreading)
+		case *ast.InlineFragment:
+			total += selectionCost(s.SelectionSet, fragments, vars, spreading)
+		case *ast.FragmentSpread:
+			def := fragments.ForName(s.Name)
+			if def == nil || spreading[s.Name] {
+				continue
+			}
+			spreading[s.Name] = true
+			total += selectionCost(def.SelectionSet, fragments, vars, spreading)
+			delete(spreading, s.Name)
+		}
+	}
+	return total
+}
+
+func fieldMultiplier(f *ast.Field, vars map[string]any) int {
+	if arg := f.Arguments.ForName("limit"); arg != nil {
+		value, err := arg.Value.Value(vars)
+		if err == nil {
+			switch v := value.(type) {
+			case int64:
+				return max(int(v), 1)
+			case float64:
+				return max(int(v), 1)
+			}
+		}
+		return maxLimit
+	}
+	if size, ok := listSizes[f.Name]; ok {
+		return size
+	}
+	return 1
+}
@@ -0,0 +1,42 @@
+package graphql
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+// loaderWait - окно, в течение которого загрузчик собирает ключи в один запрос.
+const loaderWait = 2 * time.Millisecond
+
+type loaderKey struct{}
+
+// withLoaders кладет в контекст загрузчики, живущие в пределах одного запроса.
+func (r *Resolver) withLoaders(ctx context.Context) context.Context {
+	batch := func(ctx context.Context, newsIDs []int) []*dataloader.Result[[]models.Comment] {
+		ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+		defer cancel()
+
+		byNews, err := backend.CommentsByNews(ctx, r.commentsConsumer, r.commentProducer, newsIDs)
+		results := make([]*dataloader.Result[[]models.Comment], len(newsIDs))
+		for i, id := range newsIDs {
+			results[i] = &dataloader.Result[[]models.Comment]{Data: byNews[id], Error: err}
+		}
+		return results
+	}
+	loader := dataloader.NewBatchedLoader(batch, dataloader.WithWait[int, []models.Comment](loaderWait))
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+// loadComments возвращает комментарии новости через загрузчик запроса.
+func loadComments(ctx context.Context, newsID int) ([]models.Comment, error) {
+	loader, ok := ctx.Value(loaderKey{}).(*dataloader.Loader[int, []models.Comment])
+	if !ok {
+		return nil, errors.New("comments loader is not configured")
+	}
+	return loader.Load(ctx, newsID)()
+}
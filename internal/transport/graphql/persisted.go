@@ -0,0 +1,64 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Allowlist - список разрешенных (persisted) запросов.
+// Идентификатор запроса - sha256 от текста файла в hex, как в
+// расширении persistedQuery клиентов Apollo.
+type Allowlist struct {
+	queries map[string]string
+	// Arbitrary разрешает выполнять запросы не из списка.
+	Arbitrary bool
+}
+
+// LoadAllowlist читает запросы из файлов *.graphql каталога dir.
+// Пустой dir дает пустой список.
+func LoadAllowlist(dir string, arbitrary bool) (*Allowlist, error) {
+	list := &Allowlist{queries: make(map[string]string), Arbitrary: arbitrary}
+	if dir == "" {
+		return list, nil
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.graphql"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list persisted queries: %w", err)
+	}
+	for _, file := range files {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read persisted query: %w", err)
+		}
+		list.queries[queryHash(string(raw))] = string(raw)
+	}
+	return list, nil
+}
+
+// Len возвращает количество запросов в списке.
+func (a *Allowlist) Len() int {
+	return len(a.queries)
+}
+
+// Lookup возвращает текст запроса по идентификатору.
+func (a *Allowlist) Lookup(hash string) (string, bool) {
+	query, ok := a.queries[hash]
+	return query, ok
+}
+
+// Allowed сообщает, можно ли выполнить переданный текстом запрос.
+func (a *Allowlist) Allowed(query string) bool {
+	if a.Arbitrary {
+		return true
+	}
+	_, ok := a.queries[queryHash(query)]
+	return ok
+}
+
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,355 @@
+package graphql
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/censor"
+	"apigateway/internal/models"
+	"apigateway/internal/search"
+	"apigateway/internal/sources"
+	transport "apigateway/internal/transport/http"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+const (
+	backendTimeout     = 10 * time.Second
+	sourceStatsTimeout = 3 * time.Second
+	maxLimit           = 100
+)
+
+// Resolver - корневой резолвер схемы. Обращается к сервисам через
+// те же вызовы backend, что и REST-хендлеры.
+type Resolver struct {
+	newsConsumer     *kfk.Consumer
+	commentsConsumer *kfk.Consumer
+	newsProducer     *kfk.Producer
+	commentProducer  *kfk.Producer
+	censor           *censor.Client
+	index            *search.Index
+	sources          *sources.Registry
+}
+
+// NewResolver создает корневой резолвер.
+func NewResolver(newsConsumer, commentsConsumer *kfk.Consumer, newsProducer, commentProducer *kfk.Producer, cens *censor.Client, idx *search.Index, reg *sources.Registry) *Resolver {
+	return &Resolver{
+		newsConsumer:     newsConsumer,
+		commentsConsumer: commentsConsumer,
+		newsProducer:     newsProducer,
+		commentProducer:  commentProducer,
+		censor:           cens,
+		index:            idx,
+		sources:          reg,
+	}
+}
+
+func (r *Resolver) News(ctx context.Context, args struct{ ID int32 }) (*newsResolver, error) {
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+
+	news, err := backend.GetNews(ctx, r.newsConsumer, r.newsProducer, int(args.ID))
+	if err != nil {
+		return nil, err
+	}
+	if news.NewsID == 0 {
+		return nil, nil
+	}
+	r.index.Add(news)
+	return &newsResolver{news}, nil
+}
+
+type newsListArgs struct {
+	Page    int32
+	Limit   int32
+	Sources *[]string
+}
+
+func (r *Resolver) NewsList(ctx context.Context, args newsListArgs) (*newsPageResolver, error) {
+	page, limit, err := pageArgs(args.Page, args.Limit)
+	if err != nil {
+		return nil, err
+	}
+	req := models.NewsListRequest{Page: page, Limit: limit}
+	if args.Sources != nil {
+		if err := r.checkSources(*args.Sources); err != nil {
+			return nil, err
+		}
+		req.Sources = *args.Sources
+	}
+	return r.listNews(ctx, req, page, limit, models.SortPublishedDesc)
+}
+
+type filterSetInput struct {
+	Include *[]string
+	Exclude *[]string
+}
+
+type newsFilterInput struct {
+	Categories *filterSetInput
+	Authors    *filterSetInput
+	Tags       *filterSetInput
+	Sources    *filterSetInput
+	Match      string
+	Sort       string
+	DateFrom   *gql.Time
+	DateTo     *gql.Time
+}
+
+type filteredNewsArgs struct {
+	Filter *newsFilterInput
+	Page   int32
+	Limit  int32
+}
+
+func (r *Resolver) FilteredNews(ctx context.Context, args filteredNewsArgs) (*newsPageResolver, error) {
+	page, limit, err := pageArgs(args.Page, args.Limit)
+	if err != nil {
+		return nil, err
+	}
+	req := models.FilterContentRequest{
+		NewsFilter: models.NewsFilter{Match: models.MatchAny, Sort: models.SortPublishedDesc},
+		Page:       page,
+		Limit:      limit,
+	}
+	if f := args.Filter; f != nil {
+		req.Categories = f.Categories.toModel()
+		req.Authors = f.Authors.toModel()
+		req.Tags = f.Tags.toModel()
+		req.Sources = f.Sources.toModel()
+		req.Match, req.Sort = f.Match, f.Sort
+		if f.DateFrom != nil {
+			req.DateFrom = f.DateFrom.UTC().Format(time.RFC3339)
+		}
+		if f.DateTo != nil {
+			req.DateTo = f.DateTo.UTC().Format(time.RFC3339)
+		}
+		if f.DateFrom != nil && f.DateTo != nil && !f.DateFrom.Before(f.DateTo.Time) {
+			return nil, errors.New("dateFrom must be before dateTo")
+		}
+	}
+	if err := req.NewsFilter.Validate(r.sources.Names()); err != nil {
+		return nil, err
+	}
+	return r.listNews(ctx, req, page, limit, req.Sort)
+}
+
+func (r *Resolver) listNews(ctx context.Context, req any, page, limit int, sortOrder string) (*newsPageResolver, error) {
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+
+	list, err := backend.ListNews(ctx, r.newsConsumer, r.newsProducer, req)
+	if err != nil {
+		return nil, err
+	}
+	r.index.Add(list.Items...)
+	transport.SortNewsBy(list.Items, sortOrder)
+	return &newsPageResolver{models.NewListEnvelope(list.Items, page, limit, list.Total)}, nil
+}
+
+func (r *Resolver) Comments(ctx context.Context, args struct {
+	NewsID          int32
+	IncludeCensored bool
+}) ([]*commentResolver, error) {
+	if args.IncludeCensored && !transport.IsModerator(ctx) {
+		return nil, errors.New("censored comments are available to moderators only")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+
+	comments, err := backend.Comments(ctx, r.commentsConsumer, r.commentProducer, int(args.NewsID))
+	if err != nil {
+		return nil, err
+	}
+	return commentResolvers(comments, args.IncludeCensored), nil
+}
+
+func (r *Resolver) Sources(ctx context.Context) []*sourceResolver {
+	list := r.sources.List()
+	names := make([]string, len(list))
+	for i, s := range list {
+		names[i] = s.Name
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sourceStatsTimeout)
+	defer cancel()
+	stats, _ := backend.SourceStats(ctx, r.newsConsumer, r.newsProducer, names)
+
+	resolvers := make([]*sourceResolver, len(list))
+	for i, s := range list {
+		if st, ok := stats[s.Name]; ok {
+			s.LastFetch = st.LastFetch
+			s.ArticleCount = st.ArticleCount
+		}
+		resolvers[i] = &sourceResolver{s}
+	}
+	return resolvers
+}
+
+func (r *Resolver) AddComment(ctx context.Context, args struct {
+	NewsID   int32
+	ParentID *int32
+	Content  string
+}) (*addCommentResolver, error) {
+	req := models.AddCommentRequest{NewsID: int(args.NewsID), Content: args.Content}
+	if args.ParentID != nil {
+		parentID := int(*args.ParentID)
+		req.ParentID = &parentID
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+
+	result, err := backend.SubmitComment(ctx, r.commentsConsumer, r.commentProducer, r.censor, req)
+	if err != nil {
+		return nil, err
+	}
+	return &addCommentResolver{result}, nil
+}
+
+func (r *Resolver) checkSources(names []string) error {
+	known := make(map[string]bool)
+	for _, name := range r.sources.Names() {
+		known[name] = true
+	}
+	for _, name := range names {
+		if !known[name] {
+			return fmt.Errorf("unknown source %q", name)
+		}
+	}
+	return nil
+}
+
+func (f *filterSetInput) toModel() models.FilterSet {
+	var set models.FilterSet
+	if f == nil {
+		return set
+	}
+	if f.Include != nil {
+		set.Include = *f.Include
+	}
+	if f.Exclude != nil {
+		set.Exclude = *f.Exclude
+	}
+	return set
+}
+
+func pageArgs(page, limit int32) (int, int, error) {
+	if page < 1 {
+		return 0, 0, errors.New("page must be positive")
+	}
+	if limit < 1 || limit > maxLimit {
+		return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	return int(page), int(limit), nil
+}
+
+type newsResolver struct {
+	news models.NewsFullDetailed
+}
+
+func (n *newsResolver) ID() int32             { return int32(n.news.NewsID) }
+func (n *newsResolver) Title() string         { return n.news.Title }
+func (n *newsResolver) Description() string   { return n.news.Description }
+func (n *newsResolver) Content() string       { return n.news.Content }
+func (n *newsResolver) Author() string        { return n.news.Author }
+func (n *newsResolver) PublishedAt() gql.Time { return gql.Time{Time: n.news.PublishedAt} }
+func (n *newsResolver) Source() string        { return n.news.Source }
+func (n *newsResolver) Link() string          { return n.news.Link }
+
+func (n *newsResolver) Tags() []string {
+	if n.news.Tag == nil {
+		return []string{}
+	}
+	return n.news.Tag
+}
+
+// Comments загружает комментарии через загрузчик запроса, чтобы
+// комментарии всех новостей страницы запрашивались одним сообщением.
+func (n *newsResolver) Comments(ctx context.Context) ([]*commentResolver, error) {
+	comments, err := loadComments(ctx, n.news.NewsID)
+	if err != nil {
+		return nil, err
+	}
+	return commentResolvers(comments, false), nil
+}
+
+func (n *newsResolver) CommentCount(ctx context.Context) (int32, error) {
+	comments, err := n.Comments(ctx)
+	return int32(len(comments)), err
+}
+
+type newsPageResolver struct {
+	page models.ListEnvelope[models.NewsFullDetailed]
+}
+
+func (p *newsPageResolver) Items() []*newsResolver {
+	items := make([]*newsResolver, len(p.page.Items))
+	for i, n := range p.page.Items {
+		items[i] = &newsResolver{n}
+	}
+	return items
+}
+
+func (p *newsPageResolver) Page() int32       { return int32(p.page.Page) }
+func (p *newsPageResolver) Limit() int32      { return int32(p.page.Limit) }
+func (p *newsPageResolver) Total() int32      { return int32(p.page.Total) }
+func (p *newsPageResolver) TotalPages() int32 { return int32(p.page.TotalPages) }
+func (p *newsPageResolver) HasNext() bool     { return p.page.HasNext }
+
+type commentResolver struct {
+	comment models.Comment
+}
+
+// commentResolvers отбрасывает скрытые модерацией комментарии,
+// если includeHidden не задан.
+func commentResolvers(comments []models.Comment, includeHidden bool) []*commentResolver {
+	resolvers := make([]*commentResolver, 0, len(comments))
+	for _, cm := range comments {
+		if includeHidden || cm.State() == models.ModerationApproved {
+			resolvers = append(resolvers, &commentResolver{cm})
+		}
+	}
+	return resolvers
+}
+
+func (c *commentResolver) ID() int32           { return int32(c.comment.CommentID) }
+func (c *commentResolver) NewsID() int32       { return int32(c.comment.NewsID) }
+func (c *commentResolver) Message() string     { return c.comment.Message }
+func (c *commentResolver) CreatedAt() gql.Time { return gql.Time{Time: c.comment.CreatedAt} }
+func (c *commentResolver) Moderation() string  { return string(c.comment.State()) }
+
+func (c *commentResolver) ParentID() *int32 {
+	if c.comment.ParentID == nil {
+		return nil
+	}
+	id := int32(*c.comment.ParentID)
+	return &id
+}
+
+type sourceResolver struct {
+	source models.Source
+}
+
+func (s *sourceResolver) Name() string        { return s.source.Name }
+func (s *sourceResolver) URL() string         { return s.source.URL }
+func (s *sourceResolver) ArticleCount() int32 { return int32(s.source.ArticleCount) }
+
+func (s *sourceResolver) LastFetch() *gql.Time {
+	if s.source.LastFetch == nil {
+		return nil
+	}
+	return &gql.Time{Time: *s.source.LastFetch}
+}
+
+type addCommentResolver struct {
+	result backend.CommentResult
+}
+
+func (a *addCommentResolver) Status() int32 { return int32(a.result.Status) }
+func (a *addCommentResolver) Pending() bool { return a.result.Pending }
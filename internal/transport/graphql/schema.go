@@ -0,0 +1,83 @@
+package graphql
+
+// schemaSDL - GraphQL-схема шлюза.
+const schemaSDL = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+scalar Time
+
+type Query {
+	news(id: Int!): News
+	newsList(page: Int = 1, limit: Int = 10, sources: [String!]): NewsPage!
+	filteredNews(filter: NewsFilter, page: Int = 1, limit: Int = 10): NewsPage!
+	comments(newsId: Int!, includeCensored: Boolean = false): [Comment!]!
+	sources: [Source!]!
+}
+
+type Mutation {
+	addComment(newsId: Int!, parentId: Int, content: String!): AddCommentPayload!
+}
+
+type News {
+	id: Int!
+	title: String!
+	description: String!
+	content: String!
+	author: String!
+	publishedAt: Time!
+	source: String!
+	link: String!
+	tags: [String!]!
+	comments: [Comment!]!
+	commentCount: Int!
+}
+
+type NewsPage {
+	items: [News!]!
+	page: Int!
+	limit: Int!
+	total: Int!
+	totalPages: Int!
+	hasNext: Boolean!
+}
+
+type Comment {
+	id: Int!
+	newsId: Int!
+	parentId: Int
+	message: String!
+	createdAt: Time!
+	moderation: String!
+}
+
+type Source {
+	name: String!
+	url: String!
+	lastFetch: Time
+	articleCount: Int!
+}
+
+type AddCommentPayload {
+	status: Int!
+	pending: Boolean!
+}
+
+input FilterSet {
+	include: [String!]
+	exclude: [String!]
+}
+
+input NewsFilter {
+	categories: FilterSet
+	authors: FilterSet
+	tags: FilterSet
+	sources: FilterSet
+	match: String = "any"
+	sort: String = "-published_at"
+	dateFrom: Time
+	dateTo: Time
+}
+`
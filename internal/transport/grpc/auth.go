@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	transport "apigateway/internal/transport/http"
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authorize определяет роль клиента по метаданным authorization: Bearer <token>.
+// Как и в HTTP, вызовы без токена проходят анонимно.
+func authorize(ctx context.Context, tokens map[string]string) (context.Context, error) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	role, known := tokens[token]
+	if !ok || token == "" || !known {
+		return nil, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+	return transport.WithRole(ctx, role), nil
+}
+
+func unaryAuth(tokens map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, tokens)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStream подменяет контекст потока контекстом с ролью.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamAuth(tokens map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), tokens)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	newsv1 "apigateway/api/news/v1"
+	"apigateway/internal/backend"
+	"apigateway/internal/models"
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func filterSetFromProto(set *newsv1.FilterSet) models.FilterSet {
+	return models.FilterSet{Include: set.GetInclude(), Exclude: set.GetExclude()}
+}
+
+// toStatus переводит ошибку обращения к сервисам в gRPC-статус.
+func toStatus(err error) error {
+	var cerr *backend.CommentError
+	switch {
+	case errors.As(err, &cerr):
+		return status.Error(httpToCode(cerr.Status), cerr.Message)
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, backend.ErrSend), errors.Is(err, backend.ErrReceive):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+func httpToCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
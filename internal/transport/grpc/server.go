@@ -0,0 +1,293 @@
+// Package grpc реализует gRPC API шлюза (api/news/v1) поверх тех же
+// обращений к сервисам, что и HTTP-хендлеры.
+package grpc
+
+import (
+	newsv1 "apigateway/api/news/v1"
+	"apigateway/internal/backend"
+	"apigateway/internal/censor"
+	"apigateway/internal/codec"
+	"apigateway/internal/models"
+	"apigateway/internal/search"
+	"apigateway/internal/sources"
+	"apigateway/internal/stream"
+	transport "apigateway/internal/transport/http"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+const (
+	backendTimeout = 10 * time.Second
+	defaultLimit   = 10
+	maxLimit       = 100
+)
+
+// Server - реализация newsv1.NewsServiceServer.
+type Server struct {
+	newsv1.UnimplementedNewsServiceServer
+
+	newsConsumer     *kfk.Consumer
+	commentsConsumer *kfk.Consumer
+	newsProducer     *kfk.Producer
+	commentProducer  *kfk.Producer
+	censor           *censor.Client
+	index            *search.Index
+	sources          *sources.Registry
+	newsStream       *stream.Broker[models.NewsFullDetailed]
+}
+
+// NewServer создает реализацию NewsService.
+func NewServer(newsConsumer, commentsConsumer *kfk.Consumer, newsProducer, commentProducer *kfk.Producer, cens *censor.Client, idx *search.Index, reg *sources.Registry, newsStream *stream.Broker[models.NewsFullDetailed]) *Server {
+	return &Server{
+		newsConsumer:     newsConsumer,
+		commentsConsumer: commentsConsumer,
+		newsProducer:     newsProducer,
+		commentProducer:  commentProducer,
+		censor:           cens,
+		index:            idx,
+		sources:          reg,
+		newsStream:       newsStream,
+	}
+}
+
+// Register создает gRPC-сервер с NewsService, health и reflection.
+// tokens - соответствие токен -> роль, как в HTTP AuthMiddleware.
+func Register(srv *Server, tokens map[string]string) (*grpc.Server, *health.Server) {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryAuth(tokens)),
+		grpc.ChainStreamInterceptor(streamAuth(tokens)),
+	)
+	newsv1.RegisterNewsServiceServer(s, srv)
+
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus(newsv1.NewsService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, hs)
+
+	reflection.Register(s)
+	return s, hs
+}
+
+func (s *Server) ListNews(ctx context.Context, req *newsv1.ListNewsRequest) (*newsv1.ListNewsResponse, error) {
+	page, limit, err := pageArgs(req.GetPage(), req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSources(req.GetSources()); err != nil {
+		return nil, err
+	}
+	return s.listNews(ctx, models.NewsListRequest{Page: page, Limit: limit, Sources: req.GetSources()}, page, limit, models.SortPublishedDesc)
+}
+
+func (s *Server) GetNews(ctx context.Context, req *newsv1.GetNewsRequest) (*newsv1.News, error) {
+	if req.GetId() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid news id")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+
+	news, err := backend.GetNews(ctx, s.newsConsumer, s.newsProducer, int(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if news.NewsID == 0 {
+		return nil, status.Error(codes.NotFound, "news not found")
+	}
+	s.index.Add(news)
+	return codec.NewsToProto(news), nil
+}
+
+func (s *Server) FilterNews(ctx context.Context, req *newsv1.FilterNewsRequest) (*newsv1.ListNewsResponse, error) {
+	page, limit, err := pageArgs(req.GetPage(), req.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+	filter := models.FilterContentRequest{
+		NewsFilter: models.NewsFilter{
+			Categories: filterSetFromProto(req.GetCategories()),
+			Authors:    filterSetFromProto(req.GetAuthors()),
+			Tags:       filterSetFromProto(req.GetTags()),
+			Sources:    filterSetFromProto(req.GetSources()),
+			Match:      req.GetMatch(),
+			Sort:       req.GetSort(),
+		},
+		Page:  page,
+		Limit: limit,
+	}
+	if filter.Match == "" {
+		filter.Match = models.MatchAny
+	}
+	if filter.Sort == "" {
+		filter.Sort = models.SortPublishedDesc
+	}
+	if err := filter.NewsFilter.Validate(s.sources.Names()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if req.DateFrom != nil {
+		filter.DateFrom = req.GetDateFrom().AsTime().Format(time.RFC3339)
+	}
+	if req.DateTo != nil {
+		filter.DateTo = req.GetDateTo().AsTime().Format(time.RFC3339)
+	}
+	if req.DateFrom != nil && req.DateTo != nil && !req.GetDateFrom().AsTime().Before(req.GetDateTo().AsTime()) {
+		return nil, status.Error(codes.InvalidArgument, "date_from must be before date_to")
+	}
+	return s.listNews(ctx, filter, page, limit, filter.Sort)
+}
+
+func (s *Server) listNews(ctx context.Context, req any, page, limit int, sortOrder string) (*newsv1.ListNewsResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+
+	list, err := backend.ListNews(ctx, s.newsConsumer, s.newsProducer, req)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	s.index.Add(list.Items...)
+	transport.SortNewsBy(list.Items, sortOrder)
+
+	env := models.NewListEnvelope(list.Items, page, limit, list.Total)
+	resp := &newsv1.ListNewsResponse{
+		Items:      make([]*newsv1.News, len(env.Items)),
+		Page:       int32(env.Page),
+		Limit:      int32(env.Limit),
+		Total:      int32(env.Total),
+		TotalPages: int32(env.TotalPages),
+		HasNext:    env.HasNext,
+	}
+	for i, n := range env.Items {
+		resp.Items[i] = codec.NewsToProto(n)
+	}
+	return resp, nil
+}
+
+func (s *Server) ListComments(ctx context.Context, req *newsv1.ListCommentsRequest) (*newsv1.ListCommentsResponse, error) {
+	if req.GetNewsId() < 1 {
+		return nil, status.Error(codes.InvalidArgument, "invalid news_id")
+	}
+	if req.GetIncludeCensored() && !transport.IsModerator(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "censored comments are available to moderators only")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+
+	comments, err := backend.Comments(ctx, s.commentsConsumer, s.commentProducer, int(req.GetNewsId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &newsv1.ListCommentsResponse{Items: make([]*newsv1.Comment, 0, len(comments))}
+	for _, cm := range comments {
+		if req.GetIncludeCensored() || cm.State() == models.ModerationApproved {
+			resp.Items = append(resp.Items, codec.CommentToProto(cm))
+		}
+	}
+	return resp, nil
+}
+
+func (s *Server) AddComment(ctx context.Context, req *newsv1.AddCommentRequest) (*newsv1.AddCommentResponse, error) {
+	comment := models.AddCommentRequest{NewsID: int(req.GetNewsId()), Content: req.GetContent()}
+	if req.ParentId != nil {
+		parentID := int(req.GetParentId())
+		comment.ParentID = &parentID
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backendTimeout)
+	defer cancel()
+
+	result, err := backend.SubmitComment(ctx, s.commentsConsumer, s.commentProducer, s.censor, comment)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &newsv1.AddCommentResponse{Pending: result.Pending}, nil
+}
+
+// WatchNews передает новости из того же брокера, что и SSE-поток /news/stream.
+// Клиент, не успевающий читать, отключается с ResourceExhausted и может
+// переподключиться с last_event_id.
+func (s *Server) WatchNews(req *newsv1.WatchNewsRequest, srv grpc.ServerStreamingServer[newsv1.NewsEvent]) error {
+	filter := models.NewsFilter{
+		Authors: models.FilterSet{Include: req.GetAuthors()},
+		Tags:    models.FilterSet{Include: req.GetTags()},
+		Sources: models.FilterSet{Include: req.GetSources()},
+		Match:   req.GetMatch(),
+		Sort:    models.SortPublishedDesc,
+	}
+	if filter.Match == "" {
+		filter.Match = models.MatchAny
+	}
+	if err := filter.Validate(s.sources.Names()); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, missed, err := s.newsStream.Subscribe(req.GetLastEventId(), func(n models.NewsFullDetailed) bool {
+		return transport.MatchNews(filter, n)
+	})
+	if errors.Is(err, stream.ErrTooManySubscribers) {
+		return status.Error(codes.Unavailable, "too many stream connections")
+	}
+	defer s.newsStream.Unsubscribe(sub)
+
+	for _, ev := range missed {
+		if err := srv.Send(&newsv1.NewsEvent{Id: ev.ID, News: codec.NewsToProto(ev.Data)}); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-srv.Context().Done():
+			return nil
+		case ev, ok := <-sub.C:
+			if !ok {
+				if sub.Dropped() {
+					return status.Error(codes.ResourceExhausted, "client is too slow")
+				}
+				return nil
+			}
+			if err := srv.Send(&newsv1.NewsEvent{Id: ev.ID, News: codec.NewsToProto(ev.Data)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) checkSources(names []string) error {
+	known := make(map[string]bool)
+	for _, name := range s.sources.Names() {
+		known[name] = true
+	}
+	for _, name := range names {
+		if !known[name] {
+			return status.Errorf(codes.InvalidArgument, "unknown source %q", name)
+		}
+	}
+	return nil
+}
+
+func pageArgs(page, limit int32) (int, int, error) {
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = defaultLimit
+	}
+	if page < 1 {
+		return 0, 0, status.Error(codes.InvalidArgument, "page must be positive")
+	}
+	if limit < 1 || limit > maxLimit {
+		return 0, 0, status.Error(codes.InvalidArgument, fmt.Sprintf("limit must be between 1 and %d", maxLimit))
+	}
+	return int(page), int(limit), nil
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"apigateway/internal/models"
+	"context"
+	"sync"
+)
+
+// aggregateTask - обращение к сервису, отправляющее результат в канал ответов.
+type aggregateTask func(ctx context.Context, chData chan<- models.DetailedResponse) error
+
+// gather выполняет задачи параллельно и возвращает закрытый канал с их
+// ответами. Каждая задача отправляет не больше одного ответа; ее ошибка
+// попадает в канал как DetailedResponse.Error.
+func gather(ctx context.Context, tasks []aggregateTask) <-chan models.DetailedResponse {
+	chData := make(chan models.DetailedResponse, len(tasks))
+
+	var wg sync.WaitGroup
+	wg.Add(len(tasks))
+	for _, task := range tasks {
+		go func() {
+			defer wg.Done()
+			if err := task(ctx, chData); err != nil {
+				select {
+				case chData <- models.DetailedResponse{Error: err}:
+				default:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(chData)
+	return chData
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/codec"
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"apigateway/internal/problem"
+	"apigateway/internal/search"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+const maxBatchRequestBody = 16 << 10
+
+// DefaultBatchMaxIDs - значение BatchLimits.MaxIDs по умолчанию.
+const DefaultBatchMaxIDs = 50
+
+// BatchLimits - ограничения пакетного запроса новостей.
+type BatchLimits struct {
+	// MaxIDs - максимальное число id в одном запросе.
+	MaxIDs int
+}
+
+// HandleNewsBatch Враппер для хендлера POST /api/v2/news/batch.
+// Все id отправляются сервису новостей одним запросом; id, которых нет
+// в ответе, получают ошибку 404 в своем элементе.
+func HandleNewsBatch(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, idx *search.Index, limits BatchLimits) http.HandlerFunc {
+	if limits.MaxIDs <= 0 {
+		limits.MaxIDs = DefaultBatchMaxIDs
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		sel, ok := parseFields(w, r, fields.News)
+		if !ok {
+			return
+		}
+		var req models.NewsBatchRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchRequestBody)).Decode(&req); err != nil {
+			problem.Respond(w, r, problem.Validation, "Invalid request body")
+			return
+		}
+		ids, err := batchIDs(req.IDs, limits.MaxIDs)
+		if err != nil {
+			problem.Respond(w, r, problem.Validation, err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		found, err := backend.NewsByIDs(ctx, c, p, ids)
+		if err != nil {
+			problem.Render(w, r, backendError(err, "news"))
+			return
+		}
+		codec.Render(w, r, newsBatch(ids, found, idx, sel), http.StatusOK)
+	}
+}
+
+// newsBatch раскладывает ответ сервиса по запрошенным id.
+func newsBatch(ids []int, found map[int]models.NewsFullDetailed, idx *search.Index, sel fields.Selection) models.NewsBatch {
+	batch := models.NewsBatch{Items: make(map[int]models.NewsBatchItem, len(ids))}
+	for _, id := range ids {
+		news, ok := found[id]
+		if !ok {
+			batch.Items[id] = models.NewsBatchItem{Error: batchError(problem.New(problem.NotFound, "News not found"))}
+			continue
+		}
+		idx.Add(news)
+		batch.Items[id] = models.NewsBatchItem{News: sel.Item(news)}
+	}
+	return batch
+}
+
+// batchIDs проверяет id пакета и убирает повторы.
+func batchIDs(ids []int, max int) ([]int, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("ids must not be empty")
+	}
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id < 1 {
+			return nil, fmt.Errorf("invalid news id %d", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) > max {
+		return nil, fmt.Errorf("too many ids: %d, max %d", len(unique), max)
+	}
+	return unique, nil
+}
+
+func batchError(err *problem.Error) *models.BatchError {
+	return &models.BatchError{Status: err.Kind.Status, Title: err.Kind.Title, Detail: err.Detail}
+}
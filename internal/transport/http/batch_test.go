@@ -0,0 +1,44 @@
+package http
+
+import (
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestBatchIDs(t *testing.T) {
+	got, err := batchIDs([]int{3, 1, 3, 2, 1}, 3)
+	if err != nil || !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("batchIDs() = %v, %v; want [3 1 2]", got, err)
+	}
+
+	for _, ids := range [][]int{nil, {1, 0}, {-4}, {1, 2, 3, 4}} {
+		if _, err := batchIDs(ids, 3); err == nil {
+			t.Errorf("batchIDs(%v) succeeded, want error", ids)
+		}
+	}
+}
+
+func TestNewsBatch(t *testing.T) {
+	found := map[int]models.NewsFullDetailed{
+		1: {NewsID: 1, Title: "one"},
+		3: {NewsID: 3, Title: "three"},
+		9: {NewsID: 9, Title: "not requested"},
+	}
+	batch := newsBatch([]int{1, 2, 3}, found, nil, fields.Selection{})
+
+	if len(batch.Items) != 3 {
+		t.Fatalf("got %d items, want 3: %v", len(batch.Items), batch.Items)
+	}
+	for _, id := range []int{1, 3} {
+		item := batch.Items[id]
+		if news, ok := item.News.(models.NewsFullDetailed); !ok || item.Error != nil || news.NewsID != id {
+			t.Errorf("item %d = %+v, want news %d", id, item, id)
+		}
+	}
+	if item := batch.Items[2]; item.News != nil || item.Error == nil || item.Error.Status != http.StatusNotFound {
+		t.Errorf("item 2 = %+v, want a 404 error", item)
+	}
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+// commentCountTimeout - сколько список новостей ждет сервис комментариев
+// по include=comment_count, прежде чем отдать ответ без счетчиков.
+const commentCountTimeout = time.Second
+
+// commentCounts считает видимые комментарии к новостям ids одним запросом.
+// Новости без комментариев получают 0.
+func commentCounts(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, ids []int) (map[int]int, error) {
+	counts := make(map[int]int, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+	for _, id := range ids {
+		counts[id] = 0
+	}
+	byNews, err := backend.CommentsByNews(ctx, c, p, ids)
+	if err != nil {
+		return nil, err
+	}
+	for id, comments := range byNews {
+		if _, ok := counts[id]; ok {
+			counts[id] = len(moderateComments(comments, false))
+		}
+	}
+	return counts, nil
+}
+
+// attachCommentCounts заполняет comment_count у новостей списка, если он
+// запрошен. Если сервис комментариев не ответил за commentCountTimeout,
+// список отдается без счетчиков с заголовком X-Degraded: comment_count.
+func attachCommentCounts(ctx context.Context, w http.ResponseWriter, c *kfk.Consumer, p *kfk.Producer, sel fields.Selection, items []models.NewsFullDetailed) {
+	if !sel.Includes(fields.IncludeCommentCount) || len(items) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, commentCountTimeout)
+	defer cancel()
+
+	ids := make([]int, len(items))
+	for i, n := range items {
+		ids[i] = n.NewsID
+	}
+	counts, err := commentCounts(ctx, c, p, ids)
+	if err != nil {
+		log.Printf("comment counts unavailable, responding without them: %v\n", err)
+		w.Header().Add("X-Degraded", fields.IncludeCommentCount)
+		return
+	}
+	for i := range items {
+		count := counts[items[i].NewsID]
+		items[i].CommentCount = &count
+	}
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"apigateway/internal/models"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+const (
+	commentsViewTree = "tree"
+	commentsViewFlat = "flat"
+)
+
+// buildCommentTree собирает дерево комментариев по parent_id.
+// Комментарии, чей родитель не найден, считаются корневыми.
+func buildCommentTree(comments []models.Comment) []*models.CommentNode {
+	nodes := make(map[int]*models.CommentNode, len(comments))
+	for _, c := range comments {
+		nodes[c.CommentID] = &models.CommentNode{Comment: c}
+	}
+
+	var roots []*models.CommentNode
+	for _, c := range comments {
+		node := nodes[c.CommentID]
+		if c.ParentID != nil {
+			if parent, ok := nodes[*c.ParentID]; ok && parent != node {
+				parent.Replies = append(parent.Replies, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+
+	sortCommentNodes(roots, 0)
+	return roots
+}
+
+// sortCommentNodes упорядочивает ветки по времени создания и проставляет глубину.
+func sortCommentNodes(nodes []*models.CommentNode, depth int) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].CreatedAt.Equal(nodes[j].CreatedAt) {
+			return nodes[i].CommentID < nodes[j].CommentID
+		}
+		return nodes[i].CreatedAt.Before(nodes[j].CreatedAt)
+	})
+	for _, n := range nodes {
+		n.Depth = depth
+		sortCommentNodes(n.Replies, depth+1)
+	}
+}
+
+// findCommentNode ищет комментарий в дереве по ID.
+func findCommentNode(nodes []*models.CommentNode, id int) *models.CommentNode {
+	for _, n := range nodes {
+		if n.CommentID == id {
+			return n
+		}
+		if found := findCommentNode(n.Replies, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// flattenCommentTree разворачивает дерево в плоский список в порядке обхода,
+// сохраняя глубину каждого комментария.
+func flattenCommentTree(nodes []*models.CommentNode) []*models.CommentNode {
+	var flat []*models.CommentNode
+	for _, n := range nodes {
+		flat = append(flat, &models.CommentNode{Comment: n.Comment, Depth: n.Depth})
+		flat = append(flat, flattenCommentTree(n.Replies)...)
+	}
+	return flat
+}
+
+// paginateComments возвращает страницу верхнего уровня ветки.
+func paginateComments(nodes []*models.CommentNode, page, limit int) []*models.CommentNode {
+	start := (page - 1) * limit
+	if start >= len(nodes) {
+		return []*models.CommentNode{}
+	}
+	end := start + limit
+	if end > len(nodes) {
+		end = len(nodes)
+	}
+	return nodes[start:end]
+}
+
+// buildCommentThread формирует ответ для ветки комментариев новости.
+func buildCommentThread(newsID int, comments []models.Comment, parentID *int, view string, page, limit int) (models.CommentThread, error) {
+	level := buildCommentTree(comments)
+	if parentID != nil {
+		parent := findCommentNode(level, *parentID)
+		if parent == nil {
+			return models.CommentThread{}, fmt.Errorf("comment %d not found", *parentID)
+		}
+		level = parent.Replies
+	}
+
+	items := paginateComments(level, page, limit)
+	if view == commentsViewFlat {
+		items = flattenCommentTree(items)
+	}
+	return models.CommentThread{
+		ListEnvelope: models.NewListEnvelope(items, page, limit, len(level)),
+		NewsID:       newsID,
+		ParentID:     parentID,
+		View:         view,
+	}, nil
+}
+
+// parsePositiveInt читает положительное целое из query-параметра.
+// При отсутствии параметра возвращается значение по умолчанию.
+func parsePositiveInt(query url.Values, name string, def int) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 {
+		return 0, fmt.Errorf("invalid %s parameter", name)
+	}
+	return v, nil
+}
+
+// parseOptionalID читает необязательный идентификатор из query-параметра.
+func parseOptionalID(query url.Values, name string) (*int, error) {
+	if query.Get(name) == "" {
+		return nil, nil
+	}
+	id, err := parsePositiveInt(query, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// moderateComments проставляет состояние модерации и, если includeHidden
+// не задан, убирает неодобренные комментарии. Ответы на скрытый комментарий
+// переносятся к ближайшему видимому предку.
+func moderateComments(comments []models.Comment, includeHidden bool) []models.Comment {
+	byID := make(map[int]models.Comment, len(comments))
+	for i := range comments {
+		comments[i].Moderation = comments[i].State()
+		byID[comments[i].CommentID] = comments[i]
+	}
+	if includeHidden {
+		return comments
+	}
+
+	visible := make([]models.Comment, 0, len(comments))
+	for _, c := range comments {
+		if c.Moderation != models.ModerationApproved {
+			continue
+		}
+		parentID := c.ParentID
+		for depth := 0; parentID != nil && depth < len(comments); depth++ {
+			parent, ok := byID[*parentID]
+			if !ok || parent.Moderation == models.ModerationApproved {
+				break
+			}
+			parentID = parent.ParentID
+		}
+		c.ParentID = parentID
+		visible = append(visible, c)
+	}
+	return visible
+}
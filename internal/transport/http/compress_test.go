@@ -0,0 +1,124 @@
+package http
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var largeBody = strings.Repeat("news ", 1000)
+
+// serveCompressed прогоняет запрос через CompressionMiddleware с хендлером,
+// который отдает body с заголовками header.
+func serveCompressed(r *http.Request, header http.Header, body string) *httptest.ResponseRecorder {
+	handler := CompressionMiddleware(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		io.WriteString(w, body)
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                 "",
+		"gzip":             "gzip",
+		"gzip, br":         "br",
+		"gzip, br, zstd":   "zstd",
+		"zstd;q=0.5, gzip": "gzip",
+		"br;q=0":           "",
+		"*;q=0.1, gzip":    "gzip",
+		"identity":         "",
+		"GZIP":             "gzip",
+	} {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestCompressionMiddlewareGzip(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/news", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := serveCompressed(r, http.Header{"Content-Type": {"application/json"}}, largeBody)
+
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if !slices.Contains(w.Header().Values("Vary"), "Accept-Encoding") {
+		t.Errorf("Vary = %v, want Accept-Encoding", w.Header().Values("Vary"))
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, err := io.ReadAll(zr); err != nil || string(body) != largeBody {
+		t.Errorf("decompressed body differs from the original: %v", err)
+	}
+}
+
+func TestCompressionMiddlewareVaryWithCORS(t *testing.T) {
+	handler := CORSMiddleware()(CompressionMiddleware(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, largeBody)
+	})))
+	r := httptest.NewRequest(http.MethodGet, "/news", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	vary := w.Header().Values("Vary")
+	if !slices.Contains(vary, "Origin") || !slices.Contains(vary, "Accept-Encoding") {
+		t.Errorf("Vary = %v, want Origin and Accept-Encoding", vary)
+	}
+}
+
+func TestCompressionMiddlewareSkips(t *testing.T) {
+	get := func(accept string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/news", nil)
+		r.Header.Set("Accept-Encoding", accept)
+		return r
+	}
+	head := get("gzip")
+	head.Method = http.MethodHead
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	if w := serveCompressed(get("gzip"), jsonHeader, "{}"); w.Header().Get("Content-Encoding") != "" {
+		t.Error("small body was compressed")
+	}
+	if w := serveCompressed(get(""), jsonHeader, largeBody); w.Header().Get("Content-Encoding") != "" {
+		t.Error("compressed without Accept-Encoding")
+	}
+	if w := serveCompressed(get("gzip"), http.Header{"Content-Type": {"image/png"}}, largeBody); w.Header().Get("Content-Encoding") != "" {
+		t.Error("image/png was compressed")
+	}
+	if w := serveCompressed(head, jsonHeader, largeBody); w.Header().Get("Content-Encoding") != "" {
+		t.Error("HEAD response was compressed")
+	}
+}
+
+func TestCompressionMiddlewareETag(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/news/feed.xml", nil)
+	r.Header.Set("Accept-Encoding", "br")
+
+	w := serveCompressed(r, http.Header{"Content-Type": {"application/rss+xml"}, "Etag": {`"abc"`}}, largeBody)
+	if got := w.Header().Get("ETag"); got != `W/"abc"` {
+		t.Errorf("ETag of compressed response = %q, want W/\"abc\"", got)
+	}
+
+	w = serveCompressed(r, http.Header{"Content-Type": {"application/rss+xml"}, "Etag": {`W/"abc"`}}, largeBody)
+	if got := w.Header().Get("ETag"); got != `W/"abc"` {
+		t.Errorf("weak ETag = %q, want unchanged", got)
+	}
+
+	w = serveCompressed(r, http.Header{"Content-Type": {"application/rss+xml"}, "Etag": {`"abc"`}}, "<rss/>")
+	if got := w.Header().Get("ETag"); got != `"abc"` {
+		t.Errorf("ETag of uncompressed response = %q, want unchanged", got)
+	}
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	dateOnlyLayout = "2006-01-02"
+	maxDateRange   = 366 * 24 * time.Hour
+)
+
+// dateRange - полуинтервал [From, To) публикации новостей.
+type dateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// parseDateRange разбирает параметры фильтра по дате:
+//   - from/to в формате RFC3339 или YYYY-MM-DD (to для даты без времени включает весь день);
+//   - last - относительный интервал до текущего момента (24h, 90m, 7d, 2w);
+//   - date - один день, сохранен для совместимости;
+//   - tz - часовой пояс IANA для дат без смещения, по умолчанию UTC.
+func parseDateRange(query url.Values, now time.Time) (dateRange, error) {
+	loc := time.UTC
+	if tz := query.Get("tz"); tz != "" {
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return dateRange{}, fmt.Errorf("invalid tz parameter: %q", tz)
+		}
+		loc = l
+	}
+
+	from, to, last, date := query.Get("from"), query.Get("to"), query.Get("last"), query.Get("date")
+	modes := 0
+	for _, set := range []bool{from != "" || to != "", last != "", date != ""} {
+		if set {
+			modes++
+		}
+	}
+	switch {
+	case modes == 0:
+		return dateRange{}, errors.New("one of from/to, last or date parameters is required")
+	case modes > 1:
+		return dateRange{}, errors.New("from/to, last and date parameters can't be combined")
+	}
+
+	var rng dateRange
+	switch {
+	case last != "":
+		d, err := parseRelativeDuration(last)
+		if err != nil {
+			return dateRange{}, err
+		}
+		rng = dateRange{From: now.Add(-d), To: now}
+	case date != "":
+		day, err := time.ParseInLocation(dateOnlyLayout, date, loc)
+		if err != nil {
+			return dateRange{}, fmt.Errorf("invalid date parameter: %q", date)
+		}
+		rng = dateRange{From: day, To: day.AddDate(0, 0, 1)}
+	default:
+		rng.To = now
+		if from != "" {
+			t, _, err := parseDateParam(from, loc)
+			if err != nil {
+				return dateRange{}, fmt.Errorf("invalid from parameter: %q", from)
+			}
+			rng.From = t
+		}
+		if to != "" {
+			t, dateOnly, err := parseDateParam(to, loc)
+			if err != nil {
+				return dateRange{}, fmt.Errorf("invalid to parameter: %q", to)
+			}
+			if dateOnly {
+				t = t.AddDate(0, 0, 1)
+			}
+			rng.To = t
+		}
+		if from == "" {
+			rng.From = rng.To.Add(-maxDateRange)
+		}
+	}
+
+	if !rng.From.Before(rng.To) {
+		return dateRange{}, errors.New("from must be earlier than to")
+	}
+	if rng.To.Sub(rng.From) > maxDateRange {
+		return dateRange{}, fmt.Errorf("date range must not exceed %d days", int(maxDateRange.Hours()/24))
+	}
+	rng.From, rng.To = rng.From.UTC(), rng.To.UTC()
+	return rng, nil
+}
+
+// parseDateParam разбирает RFC3339 или YYYY-MM-DD в указанном часовом поясе.
+// Неэкранированный "+" в смещении приходит из query как пробел и восстанавливается.
+func parseDateParam(raw string, loc *time.Location) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339, strings.ReplaceAll(raw, " ", "+")); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation(dateOnlyLayout, raw, loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return t, true, nil
+}
+
+// parseRelativeDuration разбирает длительность Go или число с одним суффиксом
+// d (дни) или w (недели): 36h, 7d, 2w.
+func parseRelativeDuration(raw string) (time.Duration, error) {
+	var unit time.Duration
+	if raw != "" {
+		switch raw[len(raw)-1] {
+		case 'd':
+			unit = 24 * time.Hour
+		case 'w':
+			unit = 7 * 24 * time.Hour
+		}
+	}
+
+	var d time.Duration
+	if unit > 0 {
+		digits := raw[:len(raw)-1]
+		n, err := strconv.Atoi(digits)
+		if err != nil || strings.TrimLeft(digits, "0123456789") != "" {
+			return 0, fmt.Errorf("invalid last parameter: %q", raw)
+		}
+		d = time.Duration(min(n, int(maxDateRange/unit)+1)) * unit
+	} else {
+		var err error
+		d, err = time.ParseDuration(raw)
+		if err != nil {
+			return 0, fmt.Errorf("invalid last parameter: %q", raw)
+		}
+	}
+	if d <= 0 || d > maxDateRange {
+		return 0, fmt.Errorf("last parameter must be positive and not exceed %d days", int(maxDateRange.Hours()/24))
+	}
+	return d, nil
+}
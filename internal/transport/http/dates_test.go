@@ -0,0 +1,67 @@
+package http
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseRelativeDuration(t *testing.T) {
+	day := 24 * time.Hour
+	for raw, want := range map[string]time.Duration{
+		"36h":  36 * time.Hour,
+		"90m":  90 * time.Minute,
+		"7d":   7 * day,
+		"2w":   14 * day,
+		"366d": 366 * day,
+	} {
+		if got, err := parseRelativeDuration(raw); err != nil || got != want {
+			t.Errorf("parseRelativeDuration(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+
+	for _, raw := range []string{"", "d", "0d", "-1h", "+5d", " 5d", "5wd", "5dw", "367d", "53w", "9223372036854775807d"} {
+		if _, err := parseRelativeDuration(raw); err == nil {
+			t.Errorf("parseRelativeDuration(%q) succeeded, want error", raw)
+		}
+	}
+}
+
+func TestParseDateRange(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	check := func(raw string, from, to time.Time) {
+		t.Helper()
+		query, _ := url.ParseQuery(raw)
+		rng, err := parseDateRange(query, now)
+		if err != nil {
+			t.Errorf("parseDateRange(%q) error = %v", raw, err)
+			return
+		}
+		if !rng.From.Equal(from) || !rng.To.Equal(to) {
+			t.Errorf("parseDateRange(%q) = [%v, %v), want [%v, %v)", raw, rng.From, rng.To, from, to)
+		}
+	}
+
+	check("last=2d", now.Add(-48*time.Hour), now)
+	check("date=2026-10-01", day(1), day(2))
+	// to включает весь указанный день.
+	check("from=2026-10-01&to=2026-10-02", day(1), day(3))
+	check("date=2026-10-01&tz=Europe/Moscow", day(1).Add(-3*time.Hour), day(2).Add(-3*time.Hour))
+	check("from=2026-10-01T10:00:00+03:00", day(1).Add(7*time.Hour), now)
+
+	for _, raw := range []string{
+		"",
+		"last=2d&date=2026-10-01",
+		"last=5wd",
+		"from=2026-10-02&to=2026-10-01",
+		"from=2024-01-01",
+		"date=01.10.2026",
+		"date=2026-10-01&tz=Mars/Olympus",
+	} {
+		query, _ := url.ParseQuery(raw)
+		if _, err := parseDateRange(query, now); err == nil {
+			t.Errorf("parseDateRange(%q) succeeded, want error", raw)
+		}
+	}
+}
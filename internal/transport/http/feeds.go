@@ -0,0 +1,124 @@
+package http
+
+import (
+	"apigateway/internal/codec"
+	"apigateway/internal/models"
+	"apigateway/internal/problem"
+	"apigateway/internal/sources"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+const maxFeedRequestBody = 1 << 16
+
+// HandleAdminFeeds Враппер для хендлера управления лентами.
+// POST добавляет ленту, DELETE (?name= или {name} в пути) удаляет. Доступно только администраторам.
+// Об изменении сообщается агрегатору событием FeedChangeEvent в топик topic (news_input);
+// если событие не отправлено, изменение откатывается.
+func HandleAdminFeeds(ctx context.Context, p *kfk.Producer, topic string, reg *sources.Registry, validator *sources.Validator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !requireRole(w, r, RoleAdmin) {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		var (
+			event models.FeedChangeEvent
+			src   models.Source
+			err   error
+		)
+		switch r.Method {
+		case http.MethodPost:
+			var req models.AddFeedRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFeedRequestBody)).Decode(&req); err != nil {
+				problem.Respond(w, r, problem.Validation, "Invalid request body")
+				return
+			}
+			src = models.Source{Name: strings.TrimSpace(req.Name), URL: strings.TrimSpace(req.URL)}
+			if err := validator.Validate(ctx, src.Name, src.URL); err != nil {
+				problem.Respond(w, r, problem.Unprocessable, err.Error())
+				return
+			}
+			err = reg.Add(src)
+			event.Action = models.FeedActionAdd
+		case http.MethodDelete:
+			name := r.PathValue("name")
+			if name == "" {
+				name = r.URL.Query().Get("name")
+			}
+			if name == "" {
+				problem.Respond(w, r, problem.Validation, "Invalid name parameter")
+				return
+			}
+			src, err = reg.Remove(name)
+			event.Action = models.FeedActionRemove
+		}
+
+		switch {
+		case errors.Is(err, sources.ErrExists):
+			problem.Respond(w, r, problem.Conflict, err.Error())
+			return
+		case errors.Is(err, sources.ErrNotFound):
+			problem.Respond(w, r, problem.NotFound, err.Error())
+			return
+		case err != nil:
+			log.Printf("failed to update feed list: %v\n", err)
+			problem.Respond(w, r, problem.Internal, "Failed to save feed list")
+			return
+		}
+
+		event.Name, event.URL, event.ChangedAt = src.Name, src.URL, time.Now().UTC()
+		kafkaMessage, err := json.Marshal(event)
+		if err == nil {
+			err = p.SendMessage(ctx, topic, kafkaMessage)
+		}
+		if err != nil {
+			log.Printf("failed to publish feed change event: %v\n", err)
+			if rerr := rollbackFeedChange(reg, event.Action, src); rerr != nil {
+				log.Printf("failed to roll back feed change: %v\n", rerr)
+			}
+			problem.Respond(w, r, problem.Upstream, "The aggregator was not notified, feed list is unchanged")
+			return
+		}
+
+		status := http.StatusOK
+		if r.Method == http.MethodPost {
+			status = http.StatusCreated
+		}
+		codec.Render(w, r, src, status)
+	}
+}
+
+// rollbackFeedChange отменяет изменение списка лент, о котором не удалось
+// сообщить агрегатору.
+func rollbackFeedChange(reg *sources.Registry, action string, src models.Source) error {
+	if action == models.FeedActionAdd {
+		_, err := reg.Remove(src.Name)
+		return err
+	}
+	return reg.Add(src)
+}
+
+// requireRole проверяет роль клиента и отвечает 401/403, если доступа нет.
+func requireRole(w http.ResponseWriter, r *http.Request, role string) bool {
+	switch GetRole(r.Context()) {
+	case role:
+		return true
+	case "":
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		problem.Respond(w, r, problem.Unauthorized, "Authentication required")
+		return false
+	default:
+		problem.Respond(w, r, problem.Forbidden, "Insufficient permissions")
+		return false
+	}
+}
@@ -0,0 +1,193 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/cache"
+	"apigateway/internal/feed"
+	"apigateway/internal/models"
+	"apigateway/internal/problem"
+	"apigateway/internal/search"
+	"apigateway/internal/sources"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+// Форматы ленты GoNews.
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+)
+
+const defaultFeedLimit = 20
+
+// feedParams - параметры фильтрации, которые HandleFilterContent понимает так же.
+var feedParams = []string{"category", "author", "tags", "source", "match", "sort", "from", "to", "last", "date", "tz"}
+
+// RenderedFeed - закэшированная лента с данными для условных запросов.
+type RenderedFeed struct {
+	Body         []byte
+	ETag         string
+	LastModified time.Time
+}
+
+// HandleFeed Враппер для хендлера RSS/Atom ленты GoNews.
+// Без параметров отдает последние новости, с параметрами фильтра - выборку
+// как в HandleFilterContent. Поддерживает If-None-Match и If-Modified-Since.
+func HandleFeed(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, idx *search.Index, reg *sources.Registry, feedCache *cache.TTL[RenderedFeed], format, title string) http.HandlerFunc {
+	contentType := "application/rss+xml; charset=utf-8"
+	if format == FeedFormatAtom {
+		contentType = "application/atom+xml; charset=utf-8"
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		limit, err := parsePositiveInt(query, "limit", defaultFeedLimit)
+		if err != nil || limit > maxNewsLimit {
+			problem.Respond(w, r, problem.Validation, "Invalid limit parameter")
+			return
+		}
+
+		query = feedQuery(query, limit)
+		selfLink := requestBaseURL(r) + r.URL.Path + "?" + query.Encode()
+		key := format + " " + selfLink
+		rendered, ok := feedCache.Get(key)
+		if !ok {
+			rendered, err = renderFeed(r, c, p, idx, reg, query, limit, format, title, selfLink)
+			if err != nil {
+				problem.Render(w, r, err)
+				return
+			}
+			feedCache.Set(key, rendered)
+		}
+
+		h := w.Header()
+		h.Set("Content-Type", contentType)
+		h.Set("ETag", rendered.ETag)
+		h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(feedCache.TTL().Seconds())))
+		if !rendered.LastModified.IsZero() {
+			h.Set("Last-Modified", rendered.LastModified.UTC().Format(http.TimeFormat))
+		}
+		if notModified(r, rendered) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if r.Method != http.MethodHead {
+			w.Write(rendered.Body)
+		}
+	}
+}
+
+// renderFeed запрашивает новости и формирует документ ленты.
+// selfLink строится из того же нормализованного запроса, что и ключ кэша.
+func renderFeed(r *http.Request, c *kfk.Consumer, p *kfk.Producer, idx *search.Index, reg *sources.Registry, query url.Values, limit int, format, title, selfLink string) (RenderedFeed, error) {
+	var req any = models.NewsListRequest{Limit: limit}
+	sortOrder := models.SortPublishedDesc
+	if hasFeedFilter(query) {
+		filter, err := parseNewsFilter(query, reg.Names(), time.Now())
+		if err != nil {
+			return RenderedFeed{}, problem.New(problem.Validation, err.Error())
+		}
+		filter.Page, filter.Limit = 1, limit
+		req, sortOrder = filter, filter.Sort
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	list, err := backend.ListNews(ctx, c, p, req)
+	if err != nil {
+		return RenderedFeed{}, backendError(err, "news")
+	}
+	idx.Add(list.Items...)
+	SortNewsBy(list.Items, sortOrder)
+	if len(list.Items) > limit {
+		list.Items = list.Items[:limit]
+	}
+
+	base := requestBaseURL(r)
+	ch := feed.Channel{
+		Title:       title,
+		Description: "Latest news from " + title,
+		Link:        base,
+		SelfLink:    selfLink,
+	}
+	var body []byte
+	if format == FeedFormatAtom {
+		body, err = feed.Atom(ch, list.Items)
+	} else {
+		body, err = feed.RSS(ch, list.Items)
+	}
+	if err != nil {
+		return RenderedFeed{}, err
+	}
+
+	sum := sha256.Sum256(body)
+	return RenderedFeed{
+		Body:         body,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: feed.Updated(list.Items),
+	}, nil
+}
+
+func hasFeedFilter(query url.Values) bool {
+	for _, name := range feedParams {
+		if query.Has(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// feedQuery оставляет значимые параметры запроса ленты. Из результата
+// строятся ключ кэша и ссылка self, поэтому лишние параметры первого
+// запроса не попадают в закэшированный документ.
+func feedQuery(query url.Values, limit int) url.Values {
+	q := url.Values{"limit": {strconv.Itoa(limit)}}
+	for _, name := range feedParams {
+		if v, ok := query[name]; ok {
+			q[name] = v
+		}
+	}
+	return q
+}
+
+// notModified проверяет условные заголовки запроса (RFC 9110, раздел 13).
+func notModified(r *http.Request, rendered RenderedFeed) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == rendered.ETag || tag == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !rendered.LastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !rendered.LastModified.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// requestBaseURL восстанавливает схему и хост, по которым клиент обратился к шлюзу.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestFeedQuery(t *testing.T) {
+	query, _ := url.ParseQuery("tags=go&tags=rust&source=bbc&utm_source=mail&callback=x&page=3")
+	if got, want := feedQuery(query, 20).Encode(), "limit=20&source=bbc&tags=go&tags=rust"; got != want {
+		t.Errorf("feedQuery() = %q, want %q", got, want)
+	}
+	if got := feedQuery(url.Values{}, 5).Encode(); got != "limit=5" {
+		t.Errorf("feedQuery(empty) = %q, want limit=5", got)
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	published := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	rendered := RenderedFeed{ETag: `"abc"`, LastModified: published}
+	request := func(name, value string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/news.rss", nil)
+		r.Header.Set(name, value)
+		return r
+	}
+
+	// ETag сравниваются слабо: префикс W/ не учитывается.
+	for _, inm := range []string{`"abc"`, `W/"abc"`, `"x", "abc"`, "*"} {
+		if !notModified(request("If-None-Match", inm), rendered) {
+			t.Errorf("If-None-Match %s: want 304", inm)
+		}
+	}
+	if notModified(request("If-None-Match", `"x"`), rendered) {
+		t.Error("If-None-Match with another tag: want 200")
+	}
+	if !notModified(request("If-Modified-Since", published.Format(http.TimeFormat)), rendered) {
+		t.Error("If-Modified-Since at publication time: want 304")
+	}
+	if notModified(request("If-Modified-Since", published.Add(-time.Hour).Format(http.TimeFormat)), rendered) {
+		t.Error("If-Modified-Since before publication: want 200")
+	}
+}
+
+func TestRequestBaseURL(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "http://news.example/api/v2/news.rss", nil)
+	if got := requestBaseURL(r); got != "http://news.example" {
+		t.Errorf("plain request: %q", got)
+	}
+	r.Header.Set("X-Forwarded-Proto", "javascript")
+	if got := requestBaseURL(r); got != "http://news.example" {
+		t.Errorf("bogus X-Forwarded-Proto: %q", got)
+	}
+	r.Header.Set("X-Forwarded-Proto", "https")
+	if got := requestBaseURL(r); got != "https://news.example" {
+		t.Errorf("behind proxy: %q", got)
+	}
+	r.Header.Del("X-Forwarded-Proto")
+	r.TLS = &tls.ConnectionState{}
+	if got := requestBaseURL(r); got != "https://news.example" {
+		t.Errorf("TLS: %q", got)
+	}
+}
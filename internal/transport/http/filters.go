@@ -0,0 +1,128 @@
+package http
+
+import (
+	"apigateway/internal/models"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// parseFilterSet собирает значения параметра, заданные повторением
+// (tags=a&tags=b) или через запятую (tags=a,b). Значения с префиксом "-" исключаются.
+func parseFilterSet(query url.Values, name string) models.FilterSet {
+	var set models.FilterSet
+	for _, raw := range query[name] {
+		for _, v := range splitList(raw) {
+			if excluded, ok := strings.CutPrefix(v, "-"); ok {
+				if excluded = strings.TrimSpace(excluded); excluded != "" {
+					set.Exclude = append(set.Exclude, excluded)
+				}
+				continue
+			}
+			set.Include = append(set.Include, v)
+		}
+	}
+	return set
+}
+
+// splitList разбивает значение параметра по запятым, отбрасывая пробелы
+// по краям и пустые элементы (tags=a,,b, category=).
+func splitList(raw string) []string {
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parseNewsFilter разбирает параметры фильтрации ленты и проверяет их.
+// Период публикации задается теми же параметрами, что и в HandleFilterDate.
+func parseNewsFilter(query url.Values, knownSources []string, now time.Time) (models.FilterContentRequest, error) {
+	req := models.FilterContentRequest{
+		NewsFilter: models.NewsFilter{
+			Categories: parseFilterSet(query, "category"),
+			Authors:    parseFilterSet(query, "author"),
+			Tags:       parseFilterSet(query, "tags"),
+			Sources:    parseFilterSet(query, "source"),
+			Match:      query.Get("match"),
+			Sort:       query.Get("sort"),
+		},
+	}
+	if req.Match == "" {
+		req.Match = models.MatchAny
+	}
+	if req.Sort == "" {
+		req.Sort = models.SortPublishedDesc
+	}
+	if err := req.Validate(knownSources); err != nil {
+		return models.FilterContentRequest{}, err
+	}
+
+	if query.Has("from") || query.Has("to") || query.Has("last") || query.Has("date") {
+		rng, err := parseDateRange(query, now)
+		if err != nil {
+			return models.FilterContentRequest{}, err
+		}
+		req.DateFrom = rng.From.Format(time.RFC3339)
+		req.DateTo = rng.To.Format(time.RFC3339)
+	}
+	return req, nil
+}
+
+// SortNewsBy упорядочивает новости согласно параметру sort фильтра.
+func SortNewsBy(items []models.NewsFullDetailed, order string) {
+	switch order {
+	case models.SortPublishedAsc:
+		sort.SliceStable(items, func(i, j int) bool {
+			return newsKeyBefore(newsKey(items[j]), newsKey(items[i]))
+		})
+	case models.SortTitleAsc, models.SortTitleDesc:
+		desc := order == models.SortTitleDesc
+		sort.SliceStable(items, func(i, j int) bool {
+			a, b := strings.ToLower(items[i].Title), strings.ToLower(items[j].Title)
+			if desc {
+				return a > b
+			}
+			return a < b
+		})
+	default:
+		sortNews(items)
+	}
+}
+
+// MatchNews проверяет новость по фильтру на стороне шлюза.
+// Поддерживаются источники, авторы и теги; категории новость не содержит.
+func MatchNews(f models.NewsFilter, n models.NewsFullDetailed) bool {
+	if !matchValue(f.Sources, n.Source) || !matchValue(f.Authors, n.Author) {
+		return false
+	}
+	for _, tag := range f.Tags.Exclude {
+		if slices.Contains(n.Tag, tag) {
+			return false
+		}
+	}
+	if len(f.Tags.Include) == 0 {
+		return true
+	}
+	matched := 0
+	for _, tag := range f.Tags.Include {
+		if slices.Contains(n.Tag, tag) {
+			matched++
+		}
+	}
+	if f.Match == models.MatchAll {
+		return matched == len(f.Tags.Include)
+	}
+	return matched > 0
+}
+
+func matchValue(set models.FilterSet, value string) bool {
+	if slices.Contains(set.Exclude, value) {
+		return false
+	}
+	return len(set.Include) == 0 || slices.Contains(set.Include, value)
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"apigateway/internal/models"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseFilterSet(t *testing.T) {
+	query, _ := url.ParseQuery("tags=go,-java&tags=%20rust%20,,-&author=- Ivanov")
+
+	tags := parseFilterSet(query, "tags")
+	if !slices.Equal(tags.Include, []string{"go", "rust"}) || !slices.Equal(tags.Exclude, []string{"java"}) {
+		t.Errorf("tags = %+v, want include [go rust], exclude [java]", tags)
+	}
+	authors := parseFilterSet(query, "author")
+	if len(authors.Include) != 0 || !slices.Equal(authors.Exclude, []string{"Ivanov"}) {
+		t.Errorf("author = %+v, want exclude [Ivanov]", authors)
+	}
+	if set := parseFilterSet(query, "source"); set.Include != nil || set.Exclude != nil {
+		t.Errorf("missing parameter = %+v, want empty set", set)
+	}
+}
+
+func TestParseNewsFilter(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	known := []string{"habr", "lenta"}
+
+	query, _ := url.ParseQuery("source=habr&tags=go&last=1d")
+	req, err := parseNewsFilter(query, known, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Match != models.MatchAny || req.Sort != models.SortPublishedDesc {
+		t.Errorf("defaults: match %q, sort %q", req.Match, req.Sort)
+	}
+	if req.DateFrom != "2026-10-18T12:00:00Z" || req.DateTo != "2026-10-19T12:00:00Z" {
+		t.Errorf("period = [%s, %s)", req.DateFrom, req.DateTo)
+	}
+
+	for _, raw := range []string{"source=unknown", "match=some", "sort=author", "tags=go&tags=-go", "last=5wd"} {
+		query, _ := url.ParseQuery(raw)
+		if _, err := parseNewsFilter(query, known, now); err == nil {
+			t.Errorf("parseNewsFilter(%q) succeeded, want error", raw)
+		}
+	}
+}
+
+func TestMatchNews(t *testing.T) {
+	n := models.NewsFullDetailed{Source: "habr", Author: "Ivanov", Tag: []string{"go", "kafka"}}
+	filter := func(raw string) models.NewsFilter {
+		query, _ := url.ParseQuery(raw)
+		return models.NewsFilter{
+			Authors: parseFilterSet(query, "author"),
+			Tags:    parseFilterSet(query, "tags"),
+			Sources: parseFilterSet(query, "source"),
+			Match:   query.Get("match"),
+		}
+	}
+
+	for _, raw := range []string{"", "source=habr", "tags=go,rust", "tags=go,kafka&match=all", "author=-Petrov"} {
+		if !MatchNews(filter(raw), n) {
+			t.Errorf("%q does not match", raw)
+		}
+	}
+	for _, raw := range []string{"source=lenta", "tags=go,rust&match=all", "tags=-kafka", "author=-Ivanov"} {
+		if MatchNews(filter(raw), n) {
+			t.Errorf("%q matches", raw)
+		}
+	}
+}
+
+func TestSortNewsBy(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 10, 19, h, 0, 0, 0, time.UTC) }
+	items := []models.NewsFullDetailed{
+		{NewsID: 1, Title: "beta", PublishedAt: at(2)},
+		{NewsID: 2, Title: "Alpha", PublishedAt: at(1)},
+		{NewsID: 3, Title: "gamma", PublishedAt: at(3)},
+	}
+	ids := func() []int {
+		out := make([]int, len(items))
+		for i, n := range items {
+			out[i] = n.NewsID
+		}
+		return out
+	}
+
+	SortNewsBy(items, models.SortTitleAsc)
+	if got := ids(); !slices.Equal(got, []int{2, 1, 3}) {
+		t.Errorf("by title = %v", got)
+	}
+	SortNewsBy(items, models.SortPublishedAsc)
+	if got := ids(); !slices.Equal(got, []int{2, 1, 3}) {
+		t.Errorf("by published_at = %v", got)
+	}
+	SortNewsBy(items, models.SortPublishedDesc)
+	if got := ids(); !slices.Equal(got, []int{3, 1, 2}) {
+		t.Errorf("by -published_at = %v", got)
+	}
+}
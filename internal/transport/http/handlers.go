@@ -1,242 +1,275 @@
 package http
 
 import (
+	"apigateway/internal/backend"
+	"apigateway/internal/censor"
+	"apigateway/internal/codec"
+	"apigateway/internal/fields"
 	"apigateway/internal/models"
+	"apigateway/internal/pagination"
+	"apigateway/internal/problem"
+	"apigateway/internal/search"
+	"apigateway/internal/sources"
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"net/url"
 	"strconv"
-	"strings"
-	"sync"
 	"time"
 
-	httputils "github.com/Fau1con/renderresponse"
-
 	kfk "github.com/Fau1con/kafkawrapper"
 )
 
-const DEFAULT_LIMIT = "10"
-const PAGE = "1"
+const (
+	defaultPage      = 1
+	defaultLimit     = 10
+	maxCommentsLimit = 100
+)
 
 func HandleRoot(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("GoNews Server"))
 }
 
-// HandleNewsList Враппер для хендлера
-func HandleNewsList(ctx context.Context, c *kfk.Consumer, p *kfk.Producer) http.HandlerFunc {
+// HandleNewsList Враппер для хендлера.
+// Основной режим - курсорная пагинация (cursor, limit), параметры page/n
+// поддерживаются для совместимости; размер страницы в режиме page задается
+// limit или n. include=comment_count добавляет к новостям
+// число комментариев (см. attachCommentCounts).
+func HandleNewsList(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, commentConsumer *kfk.Consumer, commentProducer *kfk.Producer, signer *pagination.Signer, idx *search.Index, reg *sources.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !httputils.ValidateMethod(w, r, http.MethodGet, http.MethodOptions) {
+		query := r.URL.Query()
+		compat := query.Has("page") || query.Has("n")
+		if compat && query.Has("cursor") {
+			problem.Respond(w, r, problem.Validation, "cursor and page/n parameters can't be combined")
 			return
 		}
-
-		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-		defer cancel()
-
-		pageStr := r.URL.Query().Get("page")
-		if pageStr == "" {
-			pageStr = PAGE
+		if query.Has("n") && query.Has("limit") {
+			problem.Respond(w, r, problem.Validation, "limit and n parameters can't be combined")
+			return
 		}
-
-		limitStr := r.URL.Query().Get("n")
-		if limitStr == "" {
-			limitStr = DEFAULT_LIMIT
+		sel, ok := parseFields(w, r, fields.NewsList)
+		if !ok {
+			return
 		}
 
-		page, err := strconv.Atoi(pageStr)
+		limitParam := "limit"
+		if query.Has("n") {
+			limitParam = "n"
+		}
+		limit, err := parsePositiveInt(query, limitParam, defaultLimit)
+		if err != nil || limit > maxNewsLimit {
+			problem.Respond(w, r, problem.Validation, fmt.Sprintf("Invalid %s parameter", limitParam))
+			return
+		}
+		page, err := parsePositiveInt(query, "page", defaultPage)
 		if err != nil {
-			log.Println("Invalid page parameter, used default parameter")
+			problem.Respond(w, r, problem.Validation, "Invalid page parameter")
+			return
 		}
-
-		limit, err := strconv.Atoi(limitStr)
+		sourceNames, err := parseSources(query["source"], reg)
 		if err != nil {
-			log.Println("Invalid limit parameter, used default parameter")
+			problem.Respond(w, r, problem.Validation, err.Error())
+			return
+		}
+
+		var cur *pagination.Cursor
+		filter := pagination.FilterHash(url.Values{"source": sourceNames})
+		req := models.NewsListRequest{Page: page, Limit: limit, Sources: sourceNames}
+		if !compat {
+			req = models.NewsListRequest{Limit: limit + 1, Sources: sourceNames}
+			if raw := query.Get("cursor"); raw != "" {
+				decoded, err := signer.Decode(raw, filter)
+				if errors.Is(err, pagination.ErrCursorMismatch) {
+					problem.Respond(w, r, problem.Validation, "Cursor does not match the source parameter")
+					return
+				}
+				if err != nil {
+					problem.Respond(w, r, problem.Validation, "Invalid cursor parameter")
+					return
+				}
+				cur = &decoded
+				key := &models.NewsCursorKey{PublishedAt: cur.PublishedAt, NewsID: cur.NewsID}
+				if cur.Direction == pagination.DirectionNext {
+					req.After = key
+				} else {
+					req.Before = key
+				}
+			}
 		}
 
-		kafkaMessage := fmt.Sprintf("/newslist/?n=%d&page=%d", limit, page)
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
 
-		err = p.SendMessage(ctx, "news_input", []byte(kafkaMessage))
+		list, err := backend.ListNews(ctx, c, p, req)
 		if err != nil {
-			httputils.RenderError(w, "Failed to write message in Kafka", http.StatusInternalServerError)
+			problem.Render(w, r, backendError(err, "news"))
 			return
 		}
+		idx.Add(list.Items...)
 
-		msg, err := c.GetMessages(ctx)
-		if err != nil {
-			httputils.RenderError(w, "Failed to read message from Kafka", http.StatusInternalServerError)
-			return
+		var result models.ListEnvelope[models.NewsFullDetailed]
+		if compat {
+			sortNews(list.Items)
+			result = newsPageEnvelope(r.URL.Path, list, page, limit, queryPageLink(query, limitParam))
+		} else {
+			items, hasMore := applyCursor(list.Items, cur, limit)
+			result = models.NewListEnvelope(items, 0, limit, list.Total)
+			result.Next, result.Prev, err = cursorLinks(signer, r.URL.Path, query, filter, items, cur, limit, hasMore)
+			if err != nil {
+				problem.Respond(w, r, problem.Internal, "Failed to build pagination links")
+				return
+			}
+			result.HasNext = result.Next != ""
 		}
 
-		httputils.RenderJSON(w, msg.Value, http.StatusOK)
+		attachCommentCounts(ctx, w, commentConsumer, commentProducer, sel, result.Items)
+		if link := linkHeader(result.Next, result.Prev); link != "" {
+			w.Header().Add("Link", link)
+		}
+		codec.Render(w, r, sel.NewsList(result), http.StatusOK)
 	}
 }
 
-// HandleFilterContent Враппер для хендлера
-func HandleFilterContent(ctx context.Context, c *kfk.Consumer, p *kfk.Producer) http.HandlerFunc {
+// HandleFilterContent Враппер для хендлера.
+// Поддерживает многозначные и исключающие фильтры (см. parseNewsFilter).
+func HandleFilterContent(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, commentConsumer *kfk.Consumer, commentProducer *kfk.Producer, idx *search.Index, reg *sources.Registry) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !httputils.ValidateMethod(w, r, http.MethodGet, http.MethodOptions) {
-			return
-		}
-
 		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 		defer cancel()
 
-		// Собираем все возможные параметры фильтрации
 		query := r.URL.Query()
-		category := query.Get("category") // Нужны ли fallbacks?
-		author := query.Get("author")
-		date := query.Get("date")
-		tags := query.Get("tags") // Нужно ли заменить на tags := query[tag] для нескольких тегов в строке?
-		limit := query.Get("limit")
-
-		var queryParams []string
-
-		if category != "" {
-			queryParams = append(queryParams, "category="+category)
-		}
-		if author != "" {
-			queryParams = append(queryParams, "author="+author)
-		}
-		if date != "" {
-			queryParams = append(queryParams, "date="+date)
-		}
-		if tags != "" {
-			queryParams = append(queryParams, "tags="+tags)
-		}
-		if limit != "" {
-			queryParams = append(queryParams, "limit="+limit)
-		} else {
-			queryParams = append(queryParams, "limit="+DEFAULT_LIMIT)
+		sel, ok := parseFields(w, r, fields.NewsList)
+		if !ok {
+			return
 		}
-
-		kafkaMessage := "newslist/filtered"
-		if len(queryParams) > 0 {
-			kafkaMessage += "?" + strings.Join(queryParams, "&")
+		req, err := parseNewsFilter(query, reg.Names(), time.Now())
+		if err != nil {
+			problem.Respond(w, r, problem.Validation, err.Error())
+			return
 		}
-
-		err := p.SendMessage(ctx, "news_input", []byte(kafkaMessage))
+		req.Page, err = parsePositiveInt(query, "page", defaultPage)
 		if err != nil {
-			httputils.RenderError(w, "Failed to write message in Kafka", http.StatusInternalServerError)
+			problem.Respond(w, r, problem.Validation, "Invalid page parameter")
+			return
+		}
+		req.Limit, err = parsePositiveInt(query, "limit", defaultLimit)
+		if err != nil || req.Limit > maxNewsLimit {
+			problem.Respond(w, r, problem.Validation, "Invalid limit parameter")
+			return
 		}
 
-		msg, err := c.GetMessages(ctx)
+		list, err := backend.ListNews(ctx, c, p, req)
 		if err != nil {
-			httputils.RenderError(w, "Failed to read message in Kafka", http.StatusInternalServerError)
+			problem.Render(w, r, backendError(err, "news"))
+			return
 		}
+		idx.Add(list.Items...)
+		SortNewsBy(list.Items, req.Sort)
+		attachCommentCounts(ctx, w, commentConsumer, commentProducer, sel, list.Items)
 
-		httputils.RenderJSON(w, msg.Value, http.StatusOK)
+		codec.Render(w, r, sel.NewsList(newsPageEnvelope(r.URL.Path, list, req.Page, req.Limit, queryPageLink(query, "limit"))), http.StatusOK)
 	}
 }
 
-// HandleFilterDate Враппер для хендлера
-func HandleFilterDate(ctx context.Context, c *kfk.Consumer, p *kfk.Producer) http.HandlerFunc {
+// HandleFilterDate Враппер для хендлера.
+// Период задается параметрами from/to, last или date (см. parseDateRange).
+func HandleFilterDate(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, commentConsumer *kfk.Consumer, commentProducer *kfk.Producer, idx *search.Index) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !httputils.ValidateMethod(w, r, http.MethodGet, http.MethodOptions) {
-			return
-		}
-
 		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 		defer cancel()
 
-		date := r.URL.Query().Get("date")
-		if date == "" {
-			httputils.RenderError(w, "Invalid date parameter", http.StatusBadRequest)
+		query := r.URL.Query()
+		sel, ok := parseFields(w, r, fields.NewsList)
+		if !ok {
+			return
 		}
-
-		err := p.SendMessage(ctx, "news_input", []byte("newslist/filtered/?date="+date))
+		rng, err := parseDateRange(query, time.Now())
+		if err != nil {
+			problem.Respond(w, r, problem.Validation, err.Error())
+			return
+		}
+		page, err := parsePositiveInt(query, "page", defaultPage)
 		if err != nil {
-			httputils.RenderError(w, "Failed to write message in Kafka", http.StatusInternalServerError)
+			problem.Respond(w, r, problem.Validation, "Invalid page parameter")
+			return
+		}
+		limit, err := parsePositiveInt(query, "limit", defaultLimit)
+		if err != nil || limit > maxNewsLimit {
+			problem.Respond(w, r, problem.Validation, "Invalid limit parameter")
+			return
 		}
 
-		msg, err := c.GetMessages(ctx)
+		list, err := backend.ListNews(ctx, c, p, models.FilterDateRequest{
+			StartDate: rng.From.Format(time.RFC3339),
+			EndDate:   rng.To.Format(time.RFC3339),
+			Page:      page,
+			Limit:     limit,
+		})
 		if err != nil {
-			httputils.RenderError(w, "Failed to read message in Kafka", http.StatusInternalServerError)
+			problem.Render(w, r, backendError(err, "news"))
+			return
 		}
+		idx.Add(list.Items...)
+		sortNews(list.Items)
+		attachCommentCounts(ctx, w, commentConsumer, commentProducer, sel, list.Items)
 
-		httputils.RenderJSON(w, msg.Value, http.StatusOK)
+		codec.Render(w, r, sel.NewsList(newsPageEnvelope(r.URL.Path, list, page, limit, queryPageLink(query, "limit"))), http.StatusOK)
 	}
 }
 
 // HandleNewsDetail Враппер для хендлера
 func HandleNewsDetail(ctx context.Context, detailConsumer, commentConsumer *kfk.Consumer, newsProducer, commentProducer *kfk.Producer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !httputils.ValidateMethod(w, r, http.MethodGet, http.MethodOptions) {
+		newsID, err := strconv.Atoi(r.URL.Query().Get("id"))
+		if err != nil || newsID < 1 {
+			problem.Respond(w, r, problem.Validation, "Invalid newsID parameter")
 			return
 		}
-
-		newsID := r.URL.Query().Get("id")
-		if newsID == "" {
-			httputils.RenderError(w, "Invalid newsID parameter", http.StatusBadRequest)
+		sel, ok := parseFields(w, r, fields.News)
+		if !ok {
 			return
 		}
 
-		chData := make(chan models.DetailedResponse, 2)
-
-		var wg sync.WaitGroup
-		wg.Add(2)
-
 		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 		defer cancel()
 
-		// Получение информации по новости
-		go func() {
-			defer wg.Done()
-			if err := detailedNewsRedirectHandler(ctx, newsID, detailConsumer, newsProducer, chData); err != nil {
-				select {
-				case chData <- models.DetailedResponse{Error: err}:
-				default:
-				}
-			}
-		}()
-
-		// Получение комментариев
-		go func() {
-			defer wg.Done()
-			if err := commentsListRedirectHandler(ctx, newsID, commentConsumer, commentProducer, chData); err != nil {
-				select {
-				case chData <- models.DetailedResponse{Error: err}:
-				default:
-				}
-			}
-		}()
-		wg.Wait()
-		close(chData)
+		chData := gather(ctx, []aggregateTask{
+			// Получение информации по новости
+			func(ctx context.Context, chData chan<- models.DetailedResponse) error {
+				return detailedNewsRedirectHandler(ctx, newsID, detailConsumer, newsProducer, chData)
+			},
+			// Получение комментариев
+			func(ctx context.Context, chData chan<- models.DetailedResponse) error {
+				return commentsListRedirectHandler(ctx, newsID, commentConsumer, commentProducer, chData)
+			},
+		})
 
 		finalResponse, err := combineResponses(chData)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError) // Стоит ли использовать renderError?
+			problem.Render(w, r, err)
 			return
 		}
-		httputils.RenderJSON(w, finalResponse, http.StatusOK)
+		codec.Render(w, r, sel.Detail(finalResponse), http.StatusOK)
 	}
 }
 
-func detailedNewsRedirectHandler(ctx context.Context, newsID string, detailConsumer *kfk.Consumer, p *kfk.Producer, chData chan<- models.DetailedResponse) error {
-	err := p.SendMessage(ctx, "news_input", []byte("/newsdetail/"+newsID))
-	if err != nil {
-		return fmt.Errorf("failed to write message in Kafka: %w", err)
-	}
-	msg, err := detailConsumer.GetMessages(ctx)
+func detailedNewsRedirectHandler(ctx context.Context, newsID int, detailConsumer *kfk.Consumer, p *kfk.Producer, chData chan<- models.DetailedResponse) error {
+	news, err := backend.GetNews(ctx, detailConsumer, p, newsID)
 	if err != nil {
-		return fmt.Errorf("error when reading message from Kafka: %w", err)
+		return backendError(err, "news")
 	}
-	newsData := models.DetailedResponse{Data: string(msg.Value)}
-	chData <- newsData
+	chData <- models.DetailedResponse{Data: news}
 	return nil
 }
 
-func commentsListRedirectHandler(ctx context.Context, newsID string, c *kfk.Consumer, p *kfk.Producer, chData chan<- models.DetailedResponse) error {
-	err := p.SendMessage(ctx, "comments_input", []byte("/comments/"+newsID))
+func commentsListRedirectHandler(ctx context.Context, newsID int, c *kfk.Consumer, p *kfk.Producer, chData chan<- models.DetailedResponse) error {
+	comments, err := backend.Comments(ctx, c, p, newsID)
 	if err != nil {
-		return fmt.Errorf("failed to write message in Kafka: %w", err)
+		return backendError(err, "comments")
 	}
-	msg, err := c.GetMessages(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to read message from Kafka: %w", err)
-	}
-	commentsData := models.DetailedResponse{Data: string(msg.Value)}
-	chData <- commentsData
+	chData <- models.DetailedResponse{Data: buildCommentTree(moderateComments(comments, false))}
 	return nil
 }
 
@@ -244,15 +277,16 @@ func commentsListRedirectHandler(ctx context.Context, newsID string, c *kfk.Cons
 func combineResponses(chData <-chan models.DetailedResponse) (models.FinalResponse, error) {
 	var finalResponse models.FinalResponse
 	for response := range chData {
-		v, ok := response.Data.(string)
-		if !ok {
-			return models.FinalResponse{}, fmt.Errorf("unexpected response type not ok: %T", v)
+		if response.Error != nil {
+			return models.FinalResponse{}, response.Error
 		}
-		if strings.Contains(v, "Title") {
+		switch v := response.Data.(type) {
+		case models.NewsFullDetailed:
 			finalResponse.News = v
-		}
-		if strings.Contains(v, "comment") {
+		case []*models.CommentNode:
 			finalResponse.Comments = v
+		default:
+			return models.FinalResponse{}, fmt.Errorf("unexpected response type: %T", v)
 		}
 	}
 	return finalResponse, nil
@@ -261,53 +295,112 @@ func combineResponses(chData <-chan models.DetailedResponse) (models.FinalRespon
 // HandleCommentsByNews Враппер для хендлера
 func HandleCommentsByNews(ctx context.Context, c *kfk.Consumer, p *kfk.Producer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !httputils.ValidateMethod(w, r, http.MethodGet, http.MethodOptions) {
+		newsID, err := strconv.Atoi(r.URL.Query().Get("newsID"))
+		if err != nil || newsID < 1 {
+			problem.Respond(w, r, problem.Validation, "Invalid newsID parameter")
 			return
 		}
-		newsID := r.URL.Query().Get("newsID")
-		if newsID == "" {
-			httputils.RenderError(w, "Invalid newsID parameter", http.StatusBadRequest)
-		}
-		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
-		defer cancel()
+		renderCommentThread(w, r, c, p, newsID)
+	}
+}
 
-		err := p.SendMessage(ctx, "comments_input", []byte("/comments/?newsID="+newsID))
+// renderCommentThread отдает ветку комментариев новости по параметрам
+// parent_id, view, include_censored, page и limit.
+func renderCommentThread(w http.ResponseWriter, r *http.Request, c *kfk.Consumer, p *kfk.Producer, newsID int) {
+	query := r.URL.Query()
+	parentID, err := parseOptionalID(query, "parent_id")
+	if err != nil {
+		problem.Respond(w, r, problem.Validation, "Invalid parent_id parameter")
+		return
+	}
+	view := query.Get("view")
+	if view == "" {
+		view = commentsViewTree
+	}
+	if view != commentsViewTree && view != commentsViewFlat {
+		problem.Respond(w, r, problem.Validation, "Invalid view parameter")
+		return
+	}
+	includeCensored := false
+	if raw := query.Get("include_censored"); raw != "" {
+		includeCensored, err = strconv.ParseBool(raw)
 		if err != nil {
-			httputils.RenderError(w, "Failed to write message in Kafka", http.StatusInternalServerError)
+			problem.Respond(w, r, problem.Validation, "Invalid include_censored parameter")
+			return
 		}
+	}
+	if includeCensored && !IsModerator(r.Context()) {
+		problem.Respond(w, r, problem.Forbidden, "Censored comments are available to moderators only")
+		return
+	}
+	page, err := parsePositiveInt(query, "page", defaultPage)
+	if err != nil {
+		problem.Respond(w, r, problem.Validation, err.Error())
+		return
+	}
+	limit, err := parsePositiveInt(query, "limit", defaultLimit)
+	if err != nil || limit > maxCommentsLimit {
+		problem.Respond(w, r, problem.Validation, "Invalid limit parameter")
+		return
+	}
 
-		msg, err := c.GetMessages(ctx)
-		if err != nil {
-			httputils.RenderError(w, "Failed to read message in Kafka", http.StatusInternalServerError)
-		}
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	comments, err := backend.Comments(ctx, c, p, newsID)
+	if err != nil {
+		problem.Render(w, r, backendError(err, "comments"))
+		return
+	}
 
-		httputils.RenderJSON(w, msg.Value, http.StatusOK)
+	comments = moderateComments(comments, includeCensored)
+	thread, err := buildCommentThread(newsID, comments, parentID, view, page, limit)
+	if err != nil {
+		problem.Respond(w, r, problem.NotFound, "Parent comment not found")
+		return
 	}
+
+	codec.Render(w, r, thread, http.StatusOK)
 }
 
-// HandleAddComment Враппер для хендлера
-func HandleAddComment(ctx context.Context, c *kfk.Consumer, p *kfk.Producer) http.HandlerFunc {
+// HandleAddComment Враппер для хендлера.
+// Достаточно параметра comment; news_id и parent_id необязательны.
+// Если передан клиент цензора, текст комментария проверяется до публикации.
+func HandleAddComment(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, cens *censor.Client) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !httputils.ValidateMethod(w, r, http.MethodPost, http.MethodOptions) {
+		query := r.URL.Query()
+		var newsID int
+		if id, err := parseOptionalID(query, "news_id"); err != nil {
+			problem.Respond(w, r, problem.Validation, "Invalid news_id parameter")
 			return
+		} else if id != nil {
+			newsID = *id
 		}
-		comment := r.URL.Query().Get("comment")
-		if comment == "" {
-			httputils.RenderError(w, "Invalid comment parameter", http.StatusBadRequest)
+		parentID, err := parseOptionalID(query, "parent_id")
+		if err != nil {
+			problem.Respond(w, r, problem.Validation, "Invalid parent_id parameter")
+			return
 		}
+
 		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 		defer cancel()
 
-		err := p.SendMessage(ctx, "comment_input", []byte("/add_comments/?comment="+comment))
-		if err != nil {
-			httputils.RenderError(w, "Failed to write message in Kafka", http.StatusInternalServerError)
-		}
-
-		msg, err := c.GetMessages(ctx)
-		if err != nil {
-			httputils.RenderError(w, "Failed to read message in Kafka", http.StatusInternalServerError)
-		}
+		renderSubmitComment(ctx, w, r, c, p, cens, models.AddCommentRequest{
+			NewsID:   newsID,
+			ParentID: parentID,
+			Content:  query.Get("comment"),
+		})
+	}
+}
 
-		httputils.RenderJSON(w, msg.Value, http.StatusCreated)
+// renderSubmitComment отправляет комментарий и отдает ответ сервиса комментариев.
+func renderSubmitComment(ctx context.Context, w http.ResponseWriter, r *http.Request, c *kfk.Consumer, p *kfk.Producer, cens *censor.Client, req models.AddCommentRequest) {
+	result, err := backend.SubmitComment(ctx, c, p, cens, req)
+	var cerr *backend.CommentError
+	if errors.As(err, &cerr) {
+		problem.Render(w, r, &problem.Error{Kind: problem.KindOf(cerr.Status), Detail: cerr.Message, Upstream: cerr.Upstream})
+		return
 	}
+
+	codec.Render(w, r, result.Body, result.Status)
 }
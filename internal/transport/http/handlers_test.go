@@ -0,0 +1,72 @@
+package http
+
+import (
+	"apigateway/internal/models"
+	"apigateway/internal/pagination"
+	"apigateway/internal/sources"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// badRequest вызывает хендлер и возвращает detail ответа 400.
+func badRequest(t *testing.T, handler http.HandlerFunc, target string) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, target, nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("%s: status = %d, want 400", target, w.Code)
+		return ""
+	}
+	var body struct {
+		Detail string `json:"detail"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	return body.Detail
+}
+
+// Запросы отклоняются до обращения к сервисам, поэтому consumer и producer не нужны.
+func TestHandleNewsListParams(t *testing.T) {
+	signer, _ := pagination.NewSigner("secret")
+	reg, err := sources.NewRegistry([]models.Source{{Name: "bbc", URL: "https://bbc.example/rss"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := HandleNewsList(context.Background(), nil, nil, nil, nil, signer, nil, reg)
+
+	cursor, _ := signer.Encode(pagination.Cursor{NewsID: 1, Direction: pagination.DirectionNext})
+	for target, want := range map[string]string{
+		"/news?cursor=abc&page=2": "cursor and page/n parameters can't be combined",
+		"/news?n=5&limit=5":       "limit and n parameters can't be combined",
+		"/news?n=0":               "Invalid n parameter",
+		"/news?page=2&limit=x":    "Invalid limit parameter",
+		"/news?page=-1":           "Invalid page parameter",
+		"/news?cursor=abc":        "Invalid cursor parameter",
+		"/news?view=tiny":         `Invalid field selection: invalid view "tiny": want short or full`,
+		// page и limit совместимы: запрос доходит до проверки источника.
+		"/news?page=2&limit=5&source=unknown": `unknown source "unknown"`,
+		// Курсор выдан для ленты без фильтра источника.
+		"/news?source=bbc&cursor=" + url.QueryEscape(cursor): "Cursor does not match the source parameter",
+	} {
+		if got := badRequest(t, handler, target); got != want {
+			t.Errorf("%s: detail = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestHandleAddCommentParams(t *testing.T) {
+	handler := HandleAddComment(context.Background(), nil, nil, nil)
+	for target, want := range map[string]string{
+		"/addcomment/?comment=":                          "Invalid comment parameter",
+		"/addcomment/?comment=hi&news_id=x":              "Invalid news_id parameter",
+		"/addcomment/?comment=hi&parent_id=1":            "Invalid news_id parameter",
+		"/addcomment/?comment=hi&news_id=1&parent_id=-1": "Invalid parent_id parameter",
+	} {
+		if got := badRequest(t, handler, target); got != want {
+			t.Errorf("%s: detail = %q, want %q", target, got, want)
+		}
+	}
+}
@@ -1,18 +1,29 @@
 package http
 
 import (
+	"apigateway/internal/problem"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 )
 
 type contextKey string
 
-const requestIDKey contextKey = "request_id"
+const (
+	requestIDKey contextKey = "request_id"
+	roleKey      contextKey = "role"
+)
+
+// Роли, выдаваемые по токенам доступа.
+const (
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
 
 // responseWriter оборачивает http.ResponseWriter для захвата статус-кода
 type responseWriter struct {
@@ -25,6 +36,53 @@ func (rw *responseWriter) WriteHeader(statusCode int) {
 	rw.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Unwrap дает http.ResponseController доступ к исходному writer (Flush для SSE).
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// RouteErrorsMiddleware заменяет текстовые ответы 404 и 405 самого ServeMux
+// ошибкой application/problem+json.
+// Заголовок Allow для 405 выставляет mux.
+func RouteErrorsMiddleware(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+		mux.ServeHTTP(&routeErrorWriter{ResponseWriter: w, r: r}, r)
+	})
+}
+
+// routeErrorWriter подменяет текстовый ответ mux на 404/405.
+type routeErrorWriter struct {
+	http.ResponseWriter
+	r        *http.Request
+	rendered bool
+}
+
+func (w *routeErrorWriter) WriteHeader(statusCode int) {
+	switch statusCode {
+	case http.StatusNotFound:
+		w.rendered = true
+		problem.Respond(w.ResponseWriter, w.r, problem.NotFound, "Route not found")
+	case http.StatusMethodNotAllowed:
+		w.rendered = true
+		problem.Respond(w.ResponseWriter, w.r, problem.MethodNotAllowed,
+			fmt.Sprintf("Method %s not allowed. Allowed: %s", w.r.Method, w.Header().Get("Allow")),
+		)
+	default:
+		w.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (w *routeErrorWriter) Write(b []byte) (int, error) {
+	if w.rendered {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
 // LoggingMiddleware логирует информацию о каждом запросе.
 func LoggingMiddleware(log *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -88,8 +146,8 @@ func CORSMiddleware() func(http.Handler) http.Handler {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Requested-With, X-Request-ID")
-			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
-			w.Header().Set("Vary", "Origin")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, Link, Deprecation, Sunset, X-Degraded")
+			w.Header().Add("Vary", "Origin")
 
 			if r.Method == http.MethodOptions {
 				w.WriteHeader(http.StatusNoContent)
@@ -99,3 +157,73 @@ func CORSMiddleware() func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// Deprecation - сроки вывода версии API из эксплуатации.
+type Deprecation struct {
+	Since  time.Time
+	Sunset time.Time
+}
+
+// DeprecationMiddleware помечает ответы устаревшего маршрута заголовками
+// Deprecation (RFC 9745) и Sunset (RFC 8594) и ссылкой на замену в новой версии API.
+// successor возвращает адрес замены для запроса, пустая строка - без ссылки.
+func DeprecationMiddleware(d Deprecation, successor func(r *http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if d.Since.IsZero() {
+				w.Header().Set("Deprecation", "true")
+			} else {
+				w.Header().Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+			}
+			if !d.Sunset.IsZero() {
+				w.Header().Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if link := successor(r); link != "" {
+				w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, link))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuthMiddleware определяет роль клиента по заголовку Authorization: Bearer <token>.
+// Запросы без токена проходят анонимно, с неизвестным токеном - отклоняются.
+func AuthMiddleware(tokens map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			role, known := tokens[token]
+			if !ok || token == "" || !known {
+				problem.Respond(w, r, problem.Unauthorized, "Invalid access token")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithRole(r.Context(), role)))
+		})
+	}
+}
+
+// WithRole кладет роль клиента в контекст.
+func WithRole(ctx context.Context, role string) context.Context {
+	return context.WithValue(ctx, roleKey, role)
+}
+
+// GetRole извлекает роль клиента из контекста
+func GetRole(ctx context.Context) string {
+	if role, ok := ctx.Value(roleKey).(string); ok {
+		return role
+	}
+	return ""
+}
+
+// IsModerator сообщает, может ли клиент видеть скрытые модерацией данные.
+func IsModerator(ctx context.Context) bool {
+	role := GetRole(ctx)
+	return role == RoleModerator || role == RoleAdmin
+}
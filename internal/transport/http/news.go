@@ -0,0 +1,181 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"apigateway/internal/pagination"
+	"apigateway/internal/problem"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const maxNewsLimit = 100
+
+// newsPageEnvelope собирает обертку для страницы ленты в режиме page/limit.
+func newsPageEnvelope(path string, list models.NewsListResponse, page, limit int, linkFn func(path string, page, limit int) string) models.ListEnvelope[models.NewsFullDetailed] {
+	return pageEnvelope(path, list.Items, page, limit, list.Total, linkFn)
+}
+
+// pageEnvelope собирает обертку страницы со ссылками на соседние страницы.
+// Если сервис не сообщил total, он оценивается снизу по полученной странице.
+func pageEnvelope[T any](path string, items []T, page, limit, total int, linkFn func(path string, page, limit int) string) models.ListEnvelope[T] {
+	if seen := (page-1)*limit + len(items); total < seen {
+		total = seen
+	}
+	env := models.NewListEnvelope(items, page, limit, total)
+	if env.HasNext {
+		env.Next = linkFn(path, page+1, limit)
+	}
+	if page > 1 {
+		env.Prev = linkFn(path, page-1, limit)
+	}
+	return env
+}
+
+// newsKey возвращает ключ сортировки новости.
+func newsKey(n models.NewsFullDetailed) models.NewsCursorKey {
+	return models.NewsCursorKey{PublishedAt: n.PublishedAt, NewsID: n.NewsID}
+}
+
+// newsKeyBefore сообщает, стоит ли ключ a в ленте раньше ключа b.
+// Лента упорядочена по убыванию published_at, при равенстве - по убыванию news_id.
+func newsKeyBefore(a, b models.NewsCursorKey) bool {
+	if a.PublishedAt.Equal(b.PublishedAt) {
+		return a.NewsID > b.NewsID
+	}
+	return a.PublishedAt.After(b.PublishedAt)
+}
+
+// sortNews упорядочивает новости в порядке ленты.
+func sortNews(items []models.NewsFullDetailed) {
+	sort.SliceStable(items, func(i, j int) bool {
+		return newsKeyBefore(newsKey(items[i]), newsKey(items[j]))
+	})
+}
+
+// applyCursor оставляет только новости строго по нужную сторону от курсора
+// и обрезает результат до limit, сообщая, есть ли еще записи в этом направлении.
+func applyCursor(items []models.NewsFullDetailed, cur *pagination.Cursor, limit int) ([]models.NewsFullDetailed, bool) {
+	sortNews(items)
+	if cur != nil {
+		key := models.NewsCursorKey{PublishedAt: cur.PublishedAt, NewsID: cur.NewsID}
+		filtered := items[:0]
+		for _, n := range items {
+			if cur.Direction == pagination.DirectionNext && newsKeyBefore(key, newsKey(n)) ||
+				cur.Direction == pagination.DirectionPrev && newsKeyBefore(newsKey(n), key) {
+				filtered = append(filtered, n)
+			}
+		}
+		items = filtered
+	}
+
+	hasMore := len(items) > limit
+	if !hasMore {
+		return items, false
+	}
+	if cur != nil && cur.Direction == pagination.DirectionPrev {
+		return items[len(items)-limit:], true
+	}
+	return items[:limit], true
+}
+
+// cursorLinks строит ссылки на соседние страницы ленты.
+// Остальные параметры исходного запроса (например, source) сохраняются,
+// а курсоры привязываются к хешу фильтров filter.
+func cursorLinks(signer *pagination.Signer, path string, query url.Values, filter string, items []models.NewsFullDetailed, cur *pagination.Cursor, limit int, hasMore bool) (next, prev string, err error) {
+	if len(items) == 0 {
+		return "", "", nil
+	}
+	forward := cur == nil || cur.Direction == pagination.DirectionNext
+
+	if !forward || hasMore {
+		last := items[len(items)-1]
+		next, err = cursorLink(signer, path, query, pagination.Cursor{PublishedAt: last.PublishedAt, NewsID: last.NewsID, Direction: pagination.DirectionNext, Filter: filter}, limit)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if (forward && cur != nil) || (!forward && hasMore) {
+		first := items[0]
+		prev, err = cursorLink(signer, path, query, pagination.Cursor{PublishedAt: first.PublishedAt, NewsID: first.NewsID, Direction: pagination.DirectionPrev, Filter: filter}, limit)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return next, prev, nil
+}
+
+func cursorLink(signer *pagination.Signer, path string, query url.Values, cur pagination.Cursor, limit int) (string, error) {
+	token, err := signer.Encode(cur)
+	if err != nil {
+		return "", err
+	}
+	q := cloneQuery(query)
+	q.Set("cursor", token)
+	q.Set("limit", strconv.Itoa(limit))
+	return path + "?" + q.Encode(), nil
+}
+
+// queryPageLink возвращает функцию построения ссылок page/limit,
+// сохраняющую остальные параметры исходного запроса.
+// limitParam - имя параметра размера страницы ("limit" или "n" в режиме совместимости).
+func queryPageLink(query url.Values, limitParam string) func(path string, page, limit int) string {
+	return func(path string, page, limit int) string {
+		q := cloneQuery(query)
+		q.Set("page", strconv.Itoa(page))
+		q.Set(limitParam, strconv.Itoa(limit))
+		return path + "?" + q.Encode()
+	}
+}
+
+func cloneQuery(query url.Values) url.Values {
+	q := make(url.Values, len(query))
+	for k, v := range query {
+		q[k] = v
+	}
+	return q
+}
+
+// linkHeader формирует заголовок Link (RFC 8288) для ссылок пагинации.
+func linkHeader(next, prev string) string {
+	var links []string
+	if next != "" {
+		links = append(links, fmt.Sprintf("<%s>; rel=\"next\"", next))
+	}
+	if prev != "" {
+		links = append(links, fmt.Sprintf("<%s>; rel=\"prev\"", prev))
+	}
+	return strings.Join(links, ", ")
+}
+
+// backendError переводит ошибку обращения к сервису в ошибку API:
+// истечение времени ожидания - 504, сбой Kafka или ответа сервиса - 502.
+func backendError(err error, service string) *problem.Error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return problem.Wrap(problem.Timeout, service, err, fmt.Sprintf("The %s service did not respond in time", service))
+	case errors.Is(err, backend.ErrBadResponse):
+		return problem.Wrap(problem.Upstream, service, err, fmt.Sprintf("Invalid response from %s service", service))
+	case errors.Is(err, backend.ErrSend), errors.Is(err, backend.ErrReceive):
+		return problem.Wrap(problem.Upstream, service, err, fmt.Sprintf("The %s service is unreachable", service))
+	default:
+		return problem.Wrap(problem.Internal, "", err, "")
+	}
+}
+
+// parseFields читает параметры fields и view; при ошибке отдает 400.
+func parseFields(w http.ResponseWriter, r *http.Request, res fields.Resource) (fields.Selection, bool) {
+	sel, err := fields.Parse(r.URL.Query(), res)
+	if err != nil {
+		problem.Respond(w, r, problem.Validation, "Invalid field selection: "+err.Error())
+		return fields.Selection{}, false
+	}
+	return sel, true
+}
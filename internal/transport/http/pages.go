@@ -0,0 +1,359 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/cache"
+	"apigateway/internal/codec"
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"apigateway/internal/problem"
+	"apigateway/internal/search"
+	"apigateway/internal/sources"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+// Виды частей составной страницы.
+const (
+	// PartNewsList - лента новостей; параметры limit, source, fields, view.
+	PartNewsList = "news_list"
+	// PartNewsFilter - выборка по фильтру; параметры как у /newslist/filtered/.
+	PartNewsFilter = "news_filter"
+	// PartSearch - поиск; параметры q, limit, fields, view.
+	PartSearch = "search"
+	// PartCommentCounts - число видимых комментариев к новостям части Of.
+	PartCommentCounts = "comment_counts"
+)
+
+const defaultPartTimeout = 3 * time.Second
+
+// Page - составная страница: части запрашиваются параллельно,
+// ответ собирается даже при ошибках отдельных частей.
+type Page struct {
+	Name     string
+	CacheTTL time.Duration
+	Parts    []PagePart
+}
+
+// PagePart - часть страницы: запрос к сервису с параметрами.
+type PagePart struct {
+	Name string
+	Kind string
+	// Of - часть с новостями, к которым относится comment_counts.
+	Of      string
+	Timeout time.Duration
+	Params  url.Values
+}
+
+// Pages - составные страницы с кэшем собранных ответов.
+type Pages struct {
+	pages  map[string]Page
+	caches map[string]*cache.TTL[models.CompositePage]
+}
+
+// NewPages проверяет описание страниц.
+func NewPages(pages []Page) (*Pages, error) {
+	ps := &Pages{
+		pages:  make(map[string]Page, len(pages)),
+		caches: make(map[string]*cache.TTL[models.CompositePage], len(pages)),
+	}
+	for _, page := range pages {
+		if err := validatePage(page); err != nil {
+			return nil, fmt.Errorf("page %q: %w", page.Name, err)
+		}
+		if _, ok := ps.pages[page.Name]; ok {
+			return nil, fmt.Errorf("page %q is defined twice", page.Name)
+		}
+		ps.pages[page.Name] = page
+		if page.CacheTTL > 0 {
+			ps.caches[page.Name] = cache.New[models.CompositePage](page.CacheTTL, 1)
+		}
+	}
+	return ps, nil
+}
+
+func validatePage(page Page) error {
+	if page.Name == "" {
+		return errors.New("name is required")
+	}
+	if len(page.Parts) == 0 {
+		return errors.New("no parts")
+	}
+	kinds := make(map[string]string, len(page.Parts))
+	for _, part := range page.Parts {
+		if part.Name == "" {
+			return errors.New("part name is required")
+		}
+		if _, ok := kinds[part.Name]; ok {
+			return fmt.Errorf("part %q is defined twice", part.Name)
+		}
+		kinds[part.Name] = part.Kind
+	}
+	for _, part := range page.Parts {
+		var err error
+		switch part.Kind {
+		case PartNewsList, PartNewsFilter:
+			_, err = fields.Parse(part.Params, fields.News)
+		case PartSearch:
+			if _, err = search.Parse(part.Params.Get("q")); err == nil {
+				_, err = fields.Parse(part.Params, fields.Search)
+			}
+		case PartCommentCounts:
+			switch kinds[part.Of] {
+			case PartNewsList, PartNewsFilter, PartSearch:
+			default:
+				err = fmt.Errorf("of must name a news part, got %q", part.Of)
+			}
+		default:
+			err = fmt.Errorf("unknown kind %q", part.Kind)
+		}
+		if err == nil && part.Params.Has("limit") {
+			if limit, perr := strconv.Atoi(part.Params.Get("limit")); perr != nil || limit < 1 || limit > maxNewsLimit {
+				err = fmt.Errorf("invalid limit %q", part.Params.Get("limit"))
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("part %q: %w", part.Name, err)
+		}
+	}
+	return nil
+}
+
+// pagePart - результат части страницы.
+type pagePart struct {
+	name string
+	kind string
+	data any
+	news []models.NewsFullDetailed
+	err  error
+}
+
+// HandlePage Враппер для хендлера GET /api/v2/pages/{name}.
+// Части без зависимостей запрашиваются параллельно, comment_counts - после
+// своих частей с новостями. Ошибка части попадает в errors, ответ помечается
+// partial и не кэшируется. Consumer отдает ответ тому, кто первым его читает,
+// поэтому части, обращающиеся к одному consumer, выполняются по очереди;
+// ожидание очереди входит в таймаут части.
+func HandlePage(ctx context.Context, newsConsumer *kfk.Consumer, newsProducer *kfk.Producer, commentsConsumer *kfk.Consumer, commentProducer *kfk.Producer, idx *search.Index, reg *sources.Registry, pages *Pages) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		page, ok := pages.pages[name]
+		if !ok {
+			problem.Respond(w, r, problem.NotFound, "Page not found")
+			return
+		}
+		pageCache := pages.caches[name]
+		if pageCache != nil {
+			if composite, ok := pageCache.Get(name); ok {
+				w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(pageCache.TTL().Seconds())))
+				codec.Render(w, r, composite, http.StatusOK)
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		var newsMu, commentsMu sync.Mutex
+		partTask := func(part PagePart, news map[string]pagePart) aggregateTask {
+			return func(ctx context.Context, chData chan<- models.DetailedResponse) error {
+				timeout := part.Timeout
+				if timeout <= 0 {
+					timeout = defaultPartTimeout
+				}
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+
+				mu := &newsMu
+				if part.Kind == PartCommentCounts {
+					mu = &commentsMu
+				}
+				mu.Lock()
+				res := pagePart{name: part.Name, kind: part.Kind}
+				switch part.Kind {
+				case PartNewsList:
+					res.data, res.news, res.err = pageNewsList(ctx, newsConsumer, newsProducer, reg, part.Params)
+				case PartNewsFilter:
+					res.data, res.news, res.err = pageNewsFilter(ctx, newsConsumer, newsProducer, reg, part.Params)
+				case PartSearch:
+					res.data, res.news, res.err = pageSearch(ctx, newsConsumer, newsProducer, idx, part.Params)
+				case PartCommentCounts:
+					res.data, res.err = pageCommentCounts(ctx, commentsConsumer, commentProducer, news[part.Of])
+				}
+				mu.Unlock()
+				if res.news != nil {
+					idx.Add(res.news...)
+				}
+				chData <- models.DetailedResponse{Data: res}
+				return nil
+			}
+		}
+
+		var independent []aggregateTask
+		for _, part := range page.Parts {
+			if part.Kind != PartCommentCounts {
+				independent = append(independent, partTask(part, nil))
+			}
+		}
+		// Карта частей с новостями заполняется до запуска comment_counts
+		// и дальше только читается их задачами.
+		news := collectParts(gather(ctx, independent))
+
+		var dependent []aggregateTask
+		for _, part := range page.Parts {
+			if part.Kind == PartCommentCounts {
+				dependent = append(dependent, partTask(part, news))
+			}
+		}
+		results := collectParts(gather(ctx, dependent))
+		maps.Copy(results, news)
+
+		composite := models.CompositePage{
+			Name:        name,
+			Parts:       make(map[string]any, len(results)),
+			GeneratedAt: time.Now().UTC(),
+		}
+		for _, res := range results {
+			if res.err == nil {
+				composite.Parts[res.name] = res.data
+				continue
+			}
+			log.Printf("page %q: part %q failed: %v\n", name, res.name, res.err)
+			if composite.Errors == nil {
+				composite.Errors = make(map[string]*models.BatchError)
+			}
+			composite.Errors[res.name] = batchError(partError(res))
+		}
+		composite.Partial = len(composite.Errors) > 0
+
+		if len(composite.Parts) == 0 {
+			problem.Respond(w, r, problem.Upstream, fmt.Sprintf("All parts of page %q failed", name))
+			return
+		}
+		if pageCache != nil && !composite.Partial {
+			pageCache.Set(name, composite)
+			w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(pageCache.TTL().Seconds())))
+		} else {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		codec.Render(w, r, composite, http.StatusOK)
+	}
+}
+
+// collectParts собирает результаты частей по имени.
+func collectParts(ch <-chan models.DetailedResponse) map[string]pagePart {
+	parts := make(map[string]pagePart)
+	for resp := range ch {
+		if res, ok := resp.Data.(pagePart); ok {
+			parts[res.name] = res
+		}
+	}
+	return parts
+}
+
+// partError переводит ошибку части в problem.Error.
+func partError(res pagePart) *problem.Error {
+	err := res.err
+	if errors.Is(err, errPartDependency) {
+		return problem.New(problem.Upstream, err.Error())
+	}
+	var verr *partParamError
+	if errors.As(err, &verr) {
+		return problem.New(problem.Validation, verr.Error())
+	}
+	if res.kind == PartCommentCounts {
+		return backendError(err, "comments")
+	}
+	return backendError(err, "news")
+}
+
+var errPartDependency = errors.New("news part failed")
+
+// partParamError - параметры части не прошли проверку при запросе
+// (например, источник удален из реестра).
+type partParamError struct {
+	err error
+}
+
+func (e *partParamError) Error() string {
+	return e.err.Error()
+}
+
+func partLimit(params url.Values) int {
+	if limit, err := strconv.Atoi(params.Get("limit")); err == nil {
+		return limit
+	}
+	return defaultLimit
+}
+
+func pageNewsList(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, reg *sources.Registry, params url.Values) (any, []models.NewsFullDetailed, error) {
+	sourceNames, err := parseSources(params["source"], reg)
+	if err != nil {
+		return nil, nil, &partParamError{err}
+	}
+	limit := partLimit(params)
+	list, err := backend.ListNews(ctx, c, p, models.NewsListRequest{Page: defaultPage, Limit: limit, Sources: sourceNames})
+	if err != nil {
+		return nil, nil, err
+	}
+	sortNews(list.Items)
+	sel, _ := fields.Parse(params, fields.News)
+	return sel.NewsList(models.NewListEnvelope(list.Items, defaultPage, limit, list.Total)), list.Items, nil
+}
+
+func pageNewsFilter(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, reg *sources.Registry, params url.Values) (any, []models.NewsFullDetailed, error) {
+	req, err := parseNewsFilter(params, reg.Names(), time.Now())
+	if err != nil {
+		return nil, nil, &partParamError{err}
+	}
+	req.Page, req.Limit = defaultPage, partLimit(params)
+	list, err := backend.ListNews(ctx, c, p, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	SortNewsBy(list.Items, req.Sort)
+	sel, _ := fields.Parse(params, fields.News)
+	return sel.NewsList(models.NewListEnvelope(list.Items, req.Page, req.Limit, list.Total)), list.Items, nil
+}
+
+func pageSearch(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, idx *search.Index, params url.Values) (any, []models.NewsFullDetailed, error) {
+	q, _ := search.Parse(params.Get("q"))
+	limit := partLimit(params)
+	resp, err := backend.Search(ctx, c, p, models.SearchRequest{Query: q.Raw, Page: defaultPage, Limit: limit})
+	if err != nil {
+		if idx == nil {
+			return nil, nil, err
+		}
+		resp.Items, resp.Total = idx.Search(q, defaultPage, limit)
+	}
+	search.Rank(resp.Items, q)
+
+	news := make([]models.NewsFullDetailed, len(resp.Items))
+	for i, hit := range resp.Items {
+		news[i] = hit.News
+	}
+	sel, _ := fields.Parse(params, fields.Search)
+	return sel.SearchList(models.NewListEnvelope(resp.Items, defaultPage, limit, resp.Total)), news, nil
+}
+
+// pageCommentCounts считает видимые комментарии к новостям части of одним запросом.
+func pageCommentCounts(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, of pagePart) (map[int]int, error) {
+	if of.err != nil {
+		return nil, fmt.Errorf("%w: %s", errPartDependency, of.name)
+	}
+	ids := make([]int, len(of.news))
+	for i, n := range of.news {
+		ids[i] = n.NewsID
+	}
+	return commentCounts(ctx, c, p, ids)
+}
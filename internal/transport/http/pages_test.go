@@ -0,0 +1,58 @@
+package http
+
+import (
+	"apigateway/internal/models"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestNewPages(t *testing.T) {
+	latest := PagePart{Name: "latest", Kind: PartNewsList, Params: url.Values{"limit": {"5"}}}
+	home := Page{Name: "home", Parts: []PagePart{
+		latest,
+		{Name: "found", Kind: PartSearch, Params: url.Values{"q": {"go"}, "fields": {"title,score"}}},
+		{Name: "counts", Kind: PartCommentCounts, Of: "latest"},
+	}}
+	if _, err := NewPages([]Page{home}); err != nil {
+		t.Fatalf("NewPages() error = %v", err)
+	}
+
+	if _, err := NewPages([]Page{home, home}); err == nil {
+		t.Error("page defined twice is accepted")
+	}
+
+	for _, parts := range [][]PagePart{
+		nil,
+		{latest, latest},
+		{{Kind: PartNewsList}},
+		{{Name: "x", Kind: "weather"}},
+		{{Name: "x", Kind: PartNewsList, Params: url.Values{"limit": {"0"}}}},
+		{{Name: "x", Kind: PartNewsList, Params: url.Values{"fields": {"secret"}}}},
+		{{Name: "x", Kind: PartSearch, Params: url.Values{"q": {`"open`}}}},
+		{latest, {Name: "counts", Kind: PartCommentCounts, Of: "missing"}},
+		{latest, {Name: "a", Kind: PartCommentCounts, Of: "latest"}, {Name: "b", Kind: PartCommentCounts, Of: "a"}},
+	} {
+		if _, err := NewPages([]Page{{Name: "home", Parts: parts}}); err == nil {
+			t.Errorf("parts %+v are accepted", parts)
+		}
+	}
+}
+
+func TestCollectParts(t *testing.T) {
+	part := func(name string) aggregateTask {
+		return func(ctx context.Context, chData chan<- models.DetailedResponse) error {
+			chData <- models.DetailedResponse{Data: pagePart{name: name}}
+			return nil
+		}
+	}
+	failing := func(ctx context.Context, chData chan<- models.DetailedResponse) error {
+		return errors.New("boom")
+	}
+
+	parts := collectParts(gather(context.Background(), []aggregateTask{part("a"), failing, part("b")}))
+	if len(parts) != 2 || parts["a"].name != "a" || parts["b"].name != "b" {
+		t.Errorf("collectParts() = %v, want parts a and b", parts)
+	}
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/censor"
+	"apigateway/internal/codec"
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"apigateway/internal/problem"
+	"apigateway/internal/search"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+const maxCommentRequestBody = 1 << 16
+
+// HandleNewsCollection Враппер для хендлера GET /api/v2/news.
+// Курсорная лента (list) отдается, пока не заданы параметры фильтра или периода
+// кроме source; с ними запрос обслуживает filtered (см. HandleFilterContent).
+func HandleNewsCollection(list, filtered http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if !query.Has("cursor") {
+			for _, name := range feedParams {
+				if name != "source" && query.Has(name) {
+					filtered.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+		list.ServeHTTP(w, r)
+	}
+}
+
+// HandleNewsItem Враппер для хендлера GET /api/v2/news/{id}.
+func HandleNewsItem(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, idx *search.Index) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		newsID, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		sel, ok := parseFields(w, r, fields.News)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		news, err := backend.GetNews(ctx, c, p, newsID)
+		if err != nil {
+			problem.Render(w, r, backendError(err, "news"))
+			return
+		}
+		if news.NewsID == 0 {
+			problem.Respond(w, r, problem.NotFound, "News not found")
+			return
+		}
+		idx.Add(news)
+		codec.Render(w, r, sel.Item(news), http.StatusOK)
+	}
+}
+
+// HandleNewsComments Враппер для хендлера GET /api/v2/news/{id}/comments.
+// Параметры ветки те же, что у /comments/.
+func HandleNewsComments(ctx context.Context, c *kfk.Consumer, p *kfk.Producer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		newsID, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		renderCommentThread(w, r, c, p, newsID)
+	}
+}
+
+// HandleCreateComment Враппер для хендлера POST /api/v2/news/{id}/comments.
+// Комментарий передается JSON-телом models.NewCommentRequest.
+func HandleCreateComment(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, cens *censor.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		newsID, ok := pathID(w, r, "id")
+		if !ok {
+			return
+		}
+		var req models.NewCommentRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCommentRequestBody)).Decode(&req); err != nil {
+			problem.Respond(w, r, problem.Validation, "Invalid request body")
+			return
+		}
+		if req.ParentID != nil && *req.ParentID < 1 {
+			problem.Respond(w, r, problem.Validation, "Invalid parent_id")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+		defer cancel()
+
+		renderSubmitComment(ctx, w, r, c, p, cens, models.AddCommentRequest{
+			NewsID:   newsID,
+			ParentID: req.ParentID,
+			Content:  req.Content,
+		})
+	}
+}
+
+// pathID читает положительный идентификатор из сегмента пути и отвечает 400, если он некорректен.
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id < 1 {
+		problem.Respond(w, r, problem.Validation, "Invalid "+name+" parameter")
+		return 0, false
+	}
+	return id, true
+}
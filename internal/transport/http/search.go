@@ -0,0 +1,68 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/codec"
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"apigateway/internal/problem"
+	"apigateway/internal/search"
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+// HandleSearch Враппер для хендлера полнотекстового поиска.
+// Если сервис новостей не ответил за backendTimeout и локальный индекс включен,
+// поиск выполняется по новостям, ранее прошедшим через шлюз.
+func HandleSearch(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, idx *search.Index, backendTimeout time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		q, err := search.Parse(query.Get("q"))
+		if err != nil {
+			problem.Respond(w, r, problem.Validation, "Invalid q parameter: "+err.Error())
+			return
+		}
+		page, err := parsePositiveInt(query, "page", defaultPage)
+		if err != nil {
+			problem.Respond(w, r, problem.Validation, "Invalid page parameter")
+			return
+		}
+		limit, err := parsePositiveInt(query, "limit", defaultLimit)
+		if err != nil || limit > maxNewsLimit {
+			problem.Respond(w, r, problem.Validation, "Invalid limit parameter")
+			return
+		}
+		sel, ok := parseFields(w, r, fields.Search)
+		if !ok {
+			return
+		}
+
+		source := "backend"
+		resp, err := searchBackend(r.Context(), c, p, models.SearchRequest{Query: q.Raw, Page: page, Limit: limit}, backendTimeout)
+		if err != nil {
+			if idx == nil {
+				problem.Render(w, r, backendError(err, "news"))
+				return
+			}
+			log.Printf("backend search failed, using local index: %v\n", err)
+			source = "local"
+			resp.Items, resp.Total = idx.Search(q, page, limit)
+		}
+		search.Rank(resp.Items, q)
+
+		w.Header().Set("X-Search-Source", source)
+		codec.Render(w, r, sel.SearchList(pageEnvelope(r.URL.Path, resp.Items, page, limit, resp.Total, queryPageLink(query, "limit"))), http.StatusOK)
+	}
+}
+
+// searchBackend отправляет типизированный поисковый запрос сервису новостей.
+func searchBackend(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, req models.SearchRequest, timeout time.Duration) (models.SearchResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return backend.Search(ctx, c, p, req)
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/codec"
+	"apigateway/internal/models"
+	"apigateway/internal/sources"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+const sourceStatsTimeout = 3 * time.Second
+
+// HandleSources Враппер для хендлера каталога источников.
+// Статистика запрашивается у сервиса новостей; если он не ответил,
+// источники возвращаются без нее.
+func HandleSources(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, reg *sources.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		list := reg.List()
+		names := make([]string, len(list))
+		for i, s := range list {
+			names[i] = s.Name
+		}
+
+		stats, err := fetchSourceStats(r.Context(), c, p, names)
+		if err != nil {
+			log.Printf("failed to get source stats: %v\n", err)
+		}
+		for i := range list {
+			if st, ok := stats[list[i].Name]; ok {
+				list[i].LastFetch = st.LastFetch
+				list[i].ArticleCount = st.ArticleCount
+			}
+		}
+
+		codec.Render(w, r, list, http.StatusOK)
+	}
+}
+
+// fetchSourceStats запрашивает статистику по источникам с коротким таймаутом.
+func fetchSourceStats(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, names []string) (map[string]models.SourceStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, sourceStatsTimeout)
+	defer cancel()
+	return backend.SourceStats(ctx, c, p, names)
+}
+
+// parseSources читает параметр source и проверяет имена по реестру.
+func parseSources(values []string, reg *sources.Registry) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	known := make(map[string]bool)
+	for _, name := range reg.Names() {
+		known[name] = true
+	}
+	var names []string
+	for _, raw := range values {
+		for _, name := range splitList(raw) {
+			if !known[name] {
+				return nil, fmt.Errorf("unknown source %q", name)
+			}
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,134 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/fields"
+	"apigateway/internal/models"
+	"apigateway/internal/problem"
+	"apigateway/internal/sources"
+	"apigateway/internal/stream"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const sseRetry = 3 * time.Second
+
+// DecodePublishedNews разбирает сообщение топика опубликованных новостей:
+// одну новость, массив или NewsListResponse.
+func DecodePublishedNews(raw []byte) ([]models.NewsFullDetailed, error) {
+	var single models.NewsFullDetailed
+	if err := json.Unmarshal(raw, &single); err == nil && single.NewsID != 0 {
+		return []models.NewsFullDetailed{single}, nil
+	}
+	list, err := backend.DecodeNewsList(raw)
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// NewsEventID - идентификатор события потока новостей: время публикации
+// в миллисекундах и id новости.
+func NewsEventID(n models.NewsFullDetailed) string {
+	return fmt.Sprintf("%d-%d", n.PublishedAt.UnixMilli(), n.NewsID)
+}
+
+// HandleNewsStream Враппер для хендлера SSE-потока новых новостей.
+// Фильтры source, author, tags и match работают как в HandleFilterContent.
+// Возобновление - по заголовку Last-Event-ID или параметру last_event_id.
+func HandleNewsStream(broker *stream.Broker[models.NewsFullDetailed], reg *sources.Registry, heartbeat time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := models.NewsFilter{
+			Authors: parseFilterSet(query, "author"),
+			Tags:    parseFilterSet(query, "tags"),
+			Sources: parseFilterSet(query, "source"),
+			Match:   query.Get("match"),
+			Sort:    models.SortPublishedDesc,
+		}
+		if filter.Match == "" {
+			filter.Match = models.MatchAny
+		}
+		if err := filter.Validate(reg.Names()); err != nil {
+			problem.Respond(w, r, problem.Validation, err.Error())
+			return
+		}
+		sel, ok := parseFields(w, r, fields.News)
+		if !ok {
+			return
+		}
+
+		lastEventID := r.Header.Get("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = query.Get("last_event_id")
+		}
+		sub, missed, err := broker.Subscribe(lastEventID, func(n models.NewsFullDetailed) bool {
+			return MatchNews(filter, n)
+		})
+		if errors.Is(err, stream.ErrTooManySubscribers) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(sseRetry.Seconds())))
+			problem.Respond(w, r, problem.Unavailable, "Too many stream connections")
+			return
+		}
+		defer broker.Unsubscribe(sub)
+
+		rc := http.NewResponseController(w)
+		h := w.Header()
+		h.Set("Content-Type", "text/event-stream")
+		h.Set("Cache-Control", "no-cache")
+		h.Set("Connection", "keep-alive")
+		h.Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+			return
+		}
+		for _, ev := range missed {
+			if err := writeNewsEvent(w, ev, sel); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			log.Printf("SSE is not supported by the response writer: %v\n", err)
+			return
+		}
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case ev, ok := <-sub.C:
+				if !ok {
+					// Клиент не успевал читать: закрываем поток, он переподключится с Last-Event-ID.
+					return
+				}
+				if err := writeNewsEvent(w, ev, sel); err != nil {
+					return
+				}
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+					return
+				}
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeNewsEvent(w http.ResponseWriter, ev stream.Event[models.NewsFullDetailed], sel fields.Selection) error {
+	data, err := json.Marshal(sel.Item(ev.Data))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: news\ndata: %s\n\n", ev.ID, data)
+	return err
+}
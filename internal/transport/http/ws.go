@@ -0,0 +1,177 @@
+package http
+
+import (
+	"apigateway/internal/backend"
+	"apigateway/internal/censor"
+	"apigateway/internal/models"
+	"apigateway/internal/problem"
+	"apigateway/internal/stream"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	kfk "github.com/Fau1con/kafkawrapper"
+)
+
+const (
+	wsReadLimit    = 16 << 10
+	wsWriteTimeout = 5 * time.Second
+	wsPingInterval = 30 * time.Second
+)
+
+// Типы сообщений WebSocket-канала комментариев.
+const (
+	wsTypeComment    = "comment"
+	wsTypeAddComment = "add_comment"
+	wsTypeAck        = "ack"
+	wsTypeError      = "error"
+)
+
+// wsMessage - сообщение канала комментариев в обе стороны.
+// Клиент отправляет add_comment (content, parent_id, request_id),
+// сервер - comment, ack и error.
+type wsMessage struct {
+	Type      string          `json:"type"`
+	EventID   string          `json:"event_id,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	Comment   *models.Comment `json:"comment,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	ParentID  *int            `json:"parent_id,omitempty"`
+	Status    int             `json:"status,omitempty"`
+	Pending   bool            `json:"pending,omitempty"`
+	Message   string          `json:"message,omitempty"`
+}
+
+// CommentEventID - идентификатор события канала комментариев: время создания
+// в миллисекундах и id комментария.
+func CommentEventID(cm models.Comment) string {
+	return fmt.Sprintf("%d-%d", cm.CreatedAt.UnixMilli(), cm.CommentID)
+}
+
+// DecodeCommentEvents разбирает сообщение топика комментариев:
+// один комментарий или массив.
+func DecodeCommentEvents(raw []byte) ([]models.Comment, error) {
+	var single models.Comment
+	if err := json.Unmarshal(raw, &single); err == nil && single.CommentID != 0 {
+		return []models.Comment{single}, nil
+	}
+	return backend.DecodeComments(raw)
+}
+
+// HandleCommentsWS Враппер для хендлера WebSocket-канала комментариев новости.
+// Раздает одобренные комментарии из топика comments и принимает новые
+// с той же проверкой, что и HandleAddComment. Клиент, не успевающий читать
+// события, отключается с кодом 1013 и может переподключиться.
+func HandleCommentsWS(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, cens *censor.Client, broker *stream.Broker[models.Comment], origins []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		newsID, err := strconv.Atoi(r.URL.Query().Get("news_id"))
+		if err != nil || newsID < 1 {
+			problem.Respond(w, r, problem.Validation, "Invalid news_id parameter")
+			return
+		}
+
+		sub, _, err := broker.Subscribe("", func(cm models.Comment) bool {
+			return cm.NewsID == newsID && cm.State() == models.ModerationApproved
+		})
+		if errors.Is(err, stream.ErrTooManySubscribers) {
+			problem.Respond(w, r, problem.Unavailable, "Too many comment connections")
+			return
+		}
+		defer broker.Unsubscribe(sub)
+
+		conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: origins})
+		if err != nil {
+			log.Printf("failed to accept websocket: %v\n", err)
+			return
+		}
+		defer conn.CloseNow()
+		conn.SetReadLimit(wsReadLimit)
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		go pushComments(ctx, cancel, conn, sub)
+
+		for {
+			var msg wsMessage
+			if err := wsjson.Read(ctx, conn, &msg); err != nil {
+				return
+			}
+			if err := writeWS(ctx, conn, handleWSMessage(ctx, c, p, cens, newsID, msg)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// pushComments отправляет клиенту события подписки и пинги.
+func pushComments(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, sub *stream.Subscription[models.Comment]) {
+	defer cancel()
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-sub.C:
+			if !ok {
+				if sub.Dropped() {
+					conn.Close(websocket.StatusTryAgainLater, "client is too slow")
+				}
+				return
+			}
+			cm := ev.Data
+			cm.Moderation = cm.State()
+			if err := writeWS(ctx, conn, wsMessage{Type: wsTypeComment, EventID: ev.ID, Comment: &cm}); err != nil {
+				return
+			}
+		case <-ping.C:
+			pingCtx, cancelPing := context.WithTimeout(ctx, wsWriteTimeout)
+			err := conn.Ping(pingCtx)
+			cancelPing()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handleWSMessage обрабатывает сообщение клиента и возвращает ответ.
+func handleWSMessage(ctx context.Context, c *kfk.Consumer, p *kfk.Producer, cens *censor.Client, newsID int, msg wsMessage) wsMessage {
+	if msg.Type != wsTypeAddComment {
+		return wsMessage{Type: wsTypeError, RequestID: msg.RequestID, Status: http.StatusBadRequest, Message: "Unknown message type"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := backend.SubmitComment(ctx, c, p, cens, models.AddCommentRequest{
+		NewsID:   newsID,
+		ParentID: msg.ParentID,
+		Content:  msg.Content,
+	})
+	var cerr *backend.CommentError
+	if errors.As(err, &cerr) {
+		return wsMessage{Type: wsTypeError, RequestID: msg.RequestID, Status: cerr.Status, Message: cerr.Message}
+	}
+	if err != nil {
+		perr := backendError(err, "comments")
+		return wsMessage{Type: wsTypeError, RequestID: msg.RequestID, Status: perr.Kind.Status, Message: perr.Kind.Title}
+	}
+	return wsMessage{Type: wsTypeAck, RequestID: msg.RequestID, Status: result.Status, Pending: result.Pending}
+}
+
+func writeWS(ctx context.Context, conn *websocket.Conn, msg wsMessage) error {
+	ctx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+	defer cancel()
+	return wsjson.Write(ctx, conn, msg)
+}